@@ -2,6 +2,8 @@ package main
 
 import (
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/alongubkin/filebox/pkg/client"
 	"github.com/alongubkin/filebox/pkg/protocol"
@@ -14,6 +16,27 @@ var (
 	verbose    = kingpin.Flag("verbose", "Verbose mode.").Short('v').Bool()
 	address    = kingpin.Flag("address", "Remote address of the Filebox server").Required().Short('r').String()
 	mountpoint = kingpin.Flag("mountpoint", "Path to mount the Filebox directory.").Required().Short('m').String()
+	username   = kingpin.Flag("username", "Username to authenticate with.").Short('u').String()
+	token      = kingpin.Flag("token", "Token or shared secret to authenticate with.").Envar("FILEBOX_TOKEN").String()
+	readOnly   = kingpin.Flag("read-only", "Mount the Filebox directory in read-only mode.").Bool()
+	cacheTTL   = kingpin.Flag("cache-ttl", "How long to cache file attributes and directory listings. 0 disables the cache.").Default("1s").Duration()
+
+	readAhead   = kingpin.Flag("read-ahead", "Number of bytes to read ahead when a file is read sequentially. 0 disables read-ahead.").Default("256KB").Bytes()
+	writeBehind = kingpin.Flag("write-behind", "Number of bytes of consecutive writes to collect before sending them to the server. 0 disables write-behind.").Default("1MB").Bytes()
+
+	reconnectAttempts = kingpin.Flag("reconnect-attempts", "Number of times to try reconnecting if the connection is lost. 0 disables reconnecting.").Default("10").Int()
+	reconnectBackoff  = kingpin.Flag("reconnect-backoff", "Delay before the first reconnect attempt. It doubles after every failed attempt.").Default("500ms").Duration()
+
+	timeout         = kingpin.Flag("timeout", "How long to wait for the server to respond to a request.").Default(client.DefaultTimeout.String()).Duration()
+	requestTimeouts = kingpin.Flag("request-timeout", "Timeout for a specific request type, e.g ReadFile=30s. Can be repeated.").PlaceHolder("REQUEST=DURATION").StringMap()
+	encoding        = kingpin.Flag("encoding", "Wire format of the connection.").Default(string(protocol.EncodingGob)).Enum(string(protocol.EncodingGob), string(protocol.EncodingProtobuf))
+	maxInFlight     = kingpin.Flag("max-in-flight", "Number of requests that can be sent to the server before their responses arrive.").Default(strconv.Itoa(client.DefaultMaxInFlight)).Int()
+
+	useTLS        = kingpin.Flag("tls", "Connect to the server using TLS.").Bool()
+	tlsCA         = kingpin.Flag("tls-ca", "Path to the CA certificates that the server's certificate is verified against. Implies --tls.").String()
+	tlsCert       = kingpin.Flag("tls-cert", "Path to the client's TLS certificate, for servers that require one. Implies --tls.").String()
+	tlsKey        = kingpin.Flag("tls-key", "Path to the private key of the client's TLS certificate.").String()
+	tlsServerName = kingpin.Flag("tls-server-name", "Server name to verify in the server's certificate, if it differs from the address.").String()
 )
 
 func main() {
@@ -25,9 +48,46 @@ func main() {
 
 	protocol.Init()
 
+	config := client.Config{
+		Address:           *address,
+		Encoding:          protocol.Encoding(*encoding),
+		Username:          *username,
+		Token:             *token,
+		ReconnectAttempts: *reconnectAttempts,
+		ReconnectBackoff:  *reconnectBackoff,
+		Timeout:           *timeout,
+		Timeouts:          make(map[string]time.Duration),
+		MaxInFlight:       *maxInFlight,
+	}
+
+	for request, value := range *requestTimeouts {
+		if !client.IsRequestName(request) {
+			log.Fatalf("Unknown request %q in --request-timeout, e.g ReadFile", request)
+			return
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatalf("Invalid timeout for %s", request)
+			return
+		}
+
+		config.Timeouts[request] = duration
+	}
+
+	if *useTLS || *tlsCA != "" || *tlsCert != "" {
+		tlsConfig, err := client.NewTLSConfig(*tlsCA, *tlsCert, *tlsKey, *tlsServerName)
+		if err != nil {
+			log.WithError(err).Fatal("Can't load TLS configuration")
+			return
+		}
+
+		config.TLSConfig = tlsConfig
+	}
+
 	exit := make(chan struct{})
 
-	c, err := client.Connect(*address, exit)
+	c, err := client.Connect(config, exit)
 	if err != nil {
 		log.WithError(err).Fatal("Can't connect to Filebox server")
 		return
@@ -35,7 +95,12 @@ func main() {
 
 	log.Info("Connected.")
 
-	fs := &client.FileboxFileSystem{Client: c}
+	fs := &client.FileboxFileSystem{
+		Client: c,
+		Cache:  client.NewMetadataCache(*cacheTTL),
+		Pages:  client.NewPageCache(c, int(*readAhead), int(*writeBehind)),
+	}
+	c.SetChangeHandler(fs.HandleChange)
 
 	host := fuse.NewFileSystemHost(fs)
 
@@ -52,10 +117,13 @@ func main() {
 		"-o", "direct_io",
 	}
 
+	if *readOnly {
+		options = append(options, "-o", "ro")
+	}
+
 	// OSX options
 	if runtime.GOOS == "darwin" {
 		options = append(options, "-o", "noappledouble")
-		options = append(options, "-o", "noapplexattr")
 	}
 
 	// Windows options
@@ -71,4 +139,13 @@ func main() {
 	}
 
 	host.Mount(*mountpoint, options)
+
+	if fs.Cache != nil {
+		stats := fs.Cache.Stats()
+		log.WithFields(log.Fields{
+			"hits":     stats.Hits,
+			"misses":   stats.Misses,
+			"hit_rate": stats.HitRate(),
+		}).Info("Metadata cache statistics")
+	}
 }
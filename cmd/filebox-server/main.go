@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strconv"
+
 	"github.com/alongubkin/filebox/pkg/protocol"
 	"github.com/alongubkin/filebox/pkg/server"
 	log "github.com/sirupsen/logrus"
@@ -11,6 +13,26 @@ var (
 	verbose = kingpin.Flag("verbose", "Verbose mode.").Short('v').Bool()
 	path    = kingpin.Flag("path", "Path to the shared directory.").Required().Short('d').String()
 	port    = kingpin.Flag("port", "TCP Port to listen on.").Required().Short('p').Uint16()
+
+	grpcPort = kingpin.Flag("grpc-port", "TCP port for the gRPC service. The service is disabled if it's not set.").Uint16()
+
+	secret     = kingpin.Flag("secret", "Shared secret that clients must present.").Envar("FILEBOX_SECRET").String()
+	tokensFile = kingpin.Flag("tokens-file", "Path to a file with a username:token pair in every line.").String()
+	policyFile = kingpin.Flag("policy", "Path to a JSON file with access rules for users.").String()
+	quotasFile = kingpin.Flag("quotas", "Path to a JSON file with storage quotas for users and directories.").String()
+	readOnly   = kingpin.Flag("read-only", "Export the shared directory in read-only mode.").Bool()
+
+	quota          = kingpin.Flag("quota", "Size of the shared directory that clients see, such as 10GB. The size of the disk by default.").Bytes()
+	mandatoryLocks = kingpin.Flag("mandatory-locks", "Reject reads and writes that conflict with byte range locks of other clients.").Bool()
+	allXattrs      = kingpin.Flag("all-xattr-namespaces", "Allow clients to change extended attributes outside the user. namespace.").Bool()
+	allowSetid     = kingpin.Flag("allow-setid", "Allow clients to set the setuid and setgid bits of files.").Bool()
+
+	maxInFlight = kingpin.Flag("max-in-flight", "Number of requests from a single client that are handled at the same time.").Default(strconv.Itoa(server.DefaultMaxInFlight)).Int()
+
+	tlsCert           = kingpin.Flag("tls-cert", "Path to the server's TLS certificate. Enables TLS.").String()
+	tlsKey            = kingpin.Flag("tls-key", "Path to the private key of the TLS certificate.").String()
+	tlsClientCA       = kingpin.Flag("tls-client-ca", "Path to the CA certificates that client certificates are verified against.").String()
+	requireClientCert = kingpin.Flag("tls-require-client-cert", "Reject clients without a valid certificate.").Bool()
 )
 
 func main() {
@@ -20,6 +42,73 @@ func main() {
 		log.SetLevel(log.TraceLevel)
 	}
 
+	config := server.Config{
+		BasePath:       *path,
+		Port:           *port,
+		GRPCPort:       *grpcPort,
+		ReadOnly:       *readOnly,
+		Quota:          uint64(*quota),
+		MandatoryLocks: *mandatoryLocks,
+		MaxInFlight:    *maxInFlight,
+
+		AllXattrNamespaces: *allXattrs,
+		AllowSetid:         *allowSetid,
+	}
+
+	if *tlsCert != "" || *tlsKey != "" {
+		tlsConfig, err := server.NewTLSConfig(*tlsCert, *tlsKey, *tlsClientCA, *requireClientCert)
+		if err != nil {
+			log.WithError(err).Fatal("Can't load TLS configuration")
+			return
+		}
+
+		config.TLSConfig = tlsConfig
+	} else if *tlsClientCA != "" || *requireClientCert {
+		log.Fatal("Client certificates require --tls-cert and --tls-key")
+		return
+	}
+
+	if *secret != "" || *tokensFile != "" {
+		config.Authenticator = &server.Authenticator{Secret: *secret}
+
+		if *tokensFile != "" {
+			if err := config.Authenticator.LoadTokens(*tokensFile); err != nil {
+				log.WithError(err).Fatal("Can't load tokens file")
+				return
+			}
+		}
+	}
+
+	if *policyFile != "" {
+		policy, err := server.LoadPolicy(*policyFile)
+		if err != nil {
+			log.WithError(err).Fatal("Can't load policy file")
+			return
+		}
+
+		if policy.HasUserRules() && *tokensFile == "" {
+			log.Fatal("Access rules for specific users require --tokens-file")
+			return
+		}
+
+		config.Policy = policy
+	}
+
+	if *quotasFile != "" {
+		quotas, err := server.LoadQuotaConfig(*quotasFile)
+		if err != nil {
+			log.WithError(err).Fatal("Can't load quotas file")
+			return
+		}
+
+		if quotas.HasUserRules() && *tokensFile == "" {
+			log.Fatal("Quotas and admins for specific users require --tokens-file")
+			return
+		}
+
+		config.Quotas = quotas
+	}
+
 	protocol.Init()
-	server.RunServer(*path, *port)
+	server.RunServer(config)
 }
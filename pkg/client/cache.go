@@ -0,0 +1,205 @@
+package client
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// MetadataCache caches file attributes and directory listings for a limited
+// time, so repeated Getattr and Readdir calls don't need a round trip to the
+// server. Entries are invalidated when this client changes them, and when the
+// server notifies about changes by other clients.
+//
+// A nil *MetadataCache is valid and caches nothing.
+type MetadataCache struct {
+	// Accessed atomically, so they must be first for 64-bit alignment on 32-bit platforms.
+	hits   uint64
+	misses uint64
+
+	ttl time.Duration
+
+	mutex       sync.Mutex
+	attributes  map[string]attributesEntry
+	directories map[string]directoryEntry
+}
+
+type attributesEntry struct {
+	fileInfo protocol.FileInfo
+	expires  time.Time
+}
+
+type directoryEntry struct {
+	files   []protocol.FileInfo
+	expires time.Time
+}
+
+// CacheStats counts the lookups in a MetadataCache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the fraction of lookups that were found in the cache.
+func (stats CacheStats) HitRate() float64 {
+	if stats.Hits+stats.Misses == 0 {
+		return 0
+	}
+
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+// NewMetadataCache creates a cache whose entries expire after ttl. If ttl
+// isn't positive, it returns nil, which disables caching.
+func NewMetadataCache(ttl time.Duration) *MetadataCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &MetadataCache{
+		ttl:         ttl,
+		attributes:  make(map[string]attributesEntry),
+		directories: make(map[string]directoryEntry),
+	}
+}
+
+// GetAttributes returns the cached attributes of a file.
+func (cache *MetadataCache) GetAttributes(filePath string) (protocol.FileInfo, bool) {
+	if cache == nil {
+		return protocol.FileInfo{}, false
+	}
+
+	cache.mutex.Lock()
+	entry, ok := cache.attributes[filePath]
+	cache.mutex.Unlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		atomic.AddUint64(&cache.misses, 1)
+		return protocol.FileInfo{}, false
+	}
+
+	atomic.AddUint64(&cache.hits, 1)
+	return entry.fileInfo, true
+}
+
+// PutAttributes caches the attributes of a file.
+func (cache *MetadataCache) PutAttributes(filePath string, fileInfo protocol.FileInfo) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.attributes[filePath] = attributesEntry{fileInfo, time.Now().Add(cache.ttl)}
+}
+
+// GetDirectory returns the cached listing of a directory.
+func (cache *MetadataCache) GetDirectory(directoryPath string) ([]protocol.FileInfo, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	cache.mutex.Lock()
+	entry, ok := cache.directories[directoryPath]
+	cache.mutex.Unlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		atomic.AddUint64(&cache.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddUint64(&cache.hits, 1)
+	return entry.files, true
+}
+
+// PutDirectory caches the listing of a directory, and the attributes of
+// every file in it.
+func (cache *MetadataCache) PutDirectory(directoryPath string, files []protocol.FileInfo) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	expires := time.Now().Add(cache.ttl)
+	cache.directories[directoryPath] = directoryEntry{files, expires}
+
+	for _, file := range files {
+		cache.attributes[path.Join(directoryPath, file.Name)] = attributesEntry{file, expires}
+	}
+}
+
+// Invalidate removes a file from the cache, along with the listing of its
+// parent directory.
+func (cache *MetadataCache) Invalidate(filePath string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.attributes, filePath)
+	delete(cache.directories, filePath)
+	delete(cache.directories, path.Dir(filePath))
+}
+
+// InvalidateTree removes a file or a directory and everything under it from
+// the cache. It's used when directories are renamed or deleted.
+func (cache *MetadataCache) InvalidateTree(filePath string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	prefix := strings.TrimSuffix(filePath, "/") + "/"
+
+	for cachedPath := range cache.attributes {
+		if cachedPath == filePath || strings.HasPrefix(cachedPath, prefix) {
+			delete(cache.attributes, cachedPath)
+		}
+	}
+
+	for cachedPath := range cache.directories {
+		if cachedPath == filePath || strings.HasPrefix(cachedPath, prefix) {
+			delete(cache.directories, cachedPath)
+		}
+	}
+
+	delete(cache.directories, path.Dir(filePath))
+}
+
+// HandleChange invalidates the entries that a change notification affects.
+func (cache *MetadataCache) HandleChange(notification protocol.ChangeNotification) {
+	switch notification.Type {
+	case protocol.ChangeRenamed:
+		cache.InvalidateTree(notification.Path)
+		cache.InvalidateTree(notification.NewPath)
+
+	case protocol.ChangeDeleted:
+		cache.InvalidateTree(notification.Path)
+
+	default:
+		cache.Invalidate(notification.Path)
+	}
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (cache *MetadataCache) Stats() CacheStats {
+	if cache == nil {
+		return CacheStats{}
+	}
+
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&cache.hits),
+		Misses: atomic.LoadUint64(&cache.misses),
+	}
+}
@@ -1,10 +1,16 @@
 package client
 
 import (
-	"encoding/gob"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
 	"net"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/alongubkin/filebox/pkg/protocol"
@@ -13,36 +19,379 @@ import (
 
 // FileboxClient is responsible for managing the client side of the Filebox protocol.
 // In order to create a new FileboxClient, use the Connect method.
+//
+// If the connection to the server is lost, the client reconnects, reopens
+// the files that were open and retries the requests that didn't get a
+// response.
 type FileboxClient struct {
-	connection    net.Conn
+	config        Config
+	exit          chan struct{}
 	nextMessageID uint32
-	encoder       *gob.Encoder
-	decoder       *gob.Decoder
 	channels      sync.Map
+	changeHandler atomic.Value
+	handles       handleTable
+	inFlight      chan struct{} // limits the number of requests that wait for a response
+
+	mutex        sync.Mutex
+	session      *session      // nil while reconnecting
+	ready        chan struct{} // closed when reconnecting is over
+	reconnecting bool          // a reconnect loop is running
+	closed       bool          // reconnecting failed
 }
 
-func Connect(address string, exit chan struct{}) (*FileboxClient, error) {
-	connection, err := net.Dial("tcp", address)
-	if err != nil {
-		return nil, err
+// session is a single connection to the server. FUSE calls the file system
+// from many threads, so requests aren't encoded directly. They are put in a
+// queue, and a single writer goroutine encodes them.
+type session struct {
+	connection net.Conn
+	encoder    protocol.Encoder
+	decoder    protocol.Decoder
+	queue      chan *protocol.Message
+	lost       chan struct{} // closed when the connection is lost
+
+	// The protocol version and capabilities that were negotiated with the server.
+	hello protocol.HelloResponse
+}
+
+// Capabilities are the optional protocol features that the client supports.
+var Capabilities = protocol.Capabilities{
+	protocol.CapabilityNotifications,
+	protocol.CapabilitySymlinks,
+}
+
+// Config contains the settings of a Filebox client.
+type Config struct {
+	Address string
+
+	// TLSConfig enables TLS on the connection. If it's nil, plain TCP is used.
+	TLSConfig *tls.Config
+
+	// Encoding is the wire format of the connection. If it's empty,
+	// protocol.EncodingGob is used.
+	Encoding protocol.Encoding
+
+	// Credentials that are presented to the server when connecting.
+	Username string
+	Token    string
+
+	// ReconnectAttempts is the number of times to try reconnecting after
+	// the connection is lost. 0 disables reconnecting.
+	ReconnectAttempts int
+
+	// ReconnectBackoff is the delay before the first reconnect attempt.
+	// It's doubled after every failed attempt, up to maxReconnectBackoff.
+	ReconnectBackoff time.Duration
+
+	// Timeout is how long to wait for the response to a request, including
+	// the time it takes to reconnect. If it's 0, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// Timeouts overrides Timeout for specific request types. The keys are
+	// request names, as returned by RequestName (e.g "ReadFile").
+	Timeouts map[string]time.Duration
+
+	// MaxInFlight is the number of requests that can be sent to the server
+	// before their responses arrive. Further requests wait until one of them
+	// completes. If it's 0, DefaultMaxInFlight is used.
+	MaxInFlight int
+}
+
+const (
+	DefaultTimeout      = 3 * time.Second
+	DefaultMaxInFlight  = 64
+	maxReconnectBackoff = 30 * time.Second
+
+	// sendQueueSize is the number of requests that can wait for the writer
+	// goroutine before senders block.
+	sendQueueSize = 128
+)
+
+var (
+	// ErrTimeout is returned by SendReceive when the server doesn't respond in time.
+	ErrTimeout = errors.New("request timed out")
+
+	// ErrClosed is returned by SendReceive when the connection to the server
+	// was lost, and reconnecting failed.
+	ErrClosed = errors.New("connection to the server is closed")
+
+	errConnectionLost = errors.New("connection to the server was lost")
+)
+
+// Connect connects to a Filebox server. exit is closed when the connection
+// is lost and can't be restored.
+func Connect(config Config, exit chan struct{}) (*FileboxClient, error) {
+	maxInFlight := config.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = DefaultMaxInFlight
 	}
 
 	client := &FileboxClient{
-		connection, 0,
-		gob.NewEncoder(connection), gob.NewDecoder(connection),
-		sync.Map{},
+		config:   config,
+		exit:     exit,
+		inFlight: make(chan struct{}, maxInFlight),
 	}
 
-	go client.handleMessages(exit)
+	session, err := client.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	client.session = session
+	go client.handleMessages(session)
+	go client.writeMessages(session)
 	return client, nil
 }
 
-func (client *FileboxClient) SendReceive(data interface{}) (interface{}, bool) {
+// dial opens a new connection to the server, negotiates the protocol version
+// and authenticates.
+func (client *FileboxClient) dial() (*session, error) {
+	session, err := client.open()
+	if err != nil {
+		return nil, err
+	}
+
+	session.hello, err = client.sayHello(session)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		// Servers that predate the hello exchange don't know HelloRequest,
+		// and close the connection when they get one.
+		log.WithError(err).Warn("Server doesn't support the hello exchange. Falling back to the legacy protocol")
+		session.connection.Close()
+
+		if session, err = client.open(); err != nil {
+			return nil, err
+		}
+		session.hello = protocol.HelloResponse{Version: protocol.LegacyVersion}
+	} else if err != nil {
+		session.connection.Close()
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"version":      session.hello.Version,
+		"capabilities": session.hello.Capabilities,
+	}).Debug("Negotiated protocol")
+
+	if err := client.authenticate(session); err != nil {
+		session.connection.Close()
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// open opens a new connection to the server.
+func (client *FileboxClient) open() (*session, error) {
+	var connection net.Conn
+	var err error
+
+	if client.config.TLSConfig != nil {
+		connection, err = tls.Dial("tcp", client.config.Address, client.config.TLSConfig)
+	} else {
+		connection, err = net.Dial("tcp", client.config.Address)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := protocol.WritePreface(connection, client.config.Encoding); err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	encoder, err := protocol.NewEncoder(client.config.Encoding, connection)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	decoder, err := protocol.NewDecoder(client.config.Encoding, connection)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	return &session{
+		connection: connection,
+		encoder:    encoder,
+		decoder:    decoder,
+		queue:      make(chan *protocol.Message, sendQueueSize),
+		lost:       make(chan struct{}),
+	}, nil
+}
+
+// sayHello announces the protocol versions and the capabilities that the
+// client supports, and returns the ones that the server agreed on.
+func (client *FileboxClient) sayHello(session *session) (protocol.HelloResponse, error) {
+	response, err := client.handshakeRequest(session, protocol.HelloRequest{
+		Version:      protocol.Version,
+		MinVersion:   protocol.MinVersion,
+		Capabilities: Capabilities,
+	})
+	if err != nil {
+		return protocol.HelloResponse{}, err
+	}
+
+	hello, ok := response.(protocol.HelloResponse)
+	if !ok {
+		return protocol.HelloResponse{}, errors.New("expected a HelloResponse message")
+	}
+
+	return hello, nil
+}
+
+// authenticate presents the client's credentials to the server. It must be
+// called right after the hello exchange, before any other request is sent.
+func (client *FileboxClient) authenticate(session *session) error {
+	_, err := client.handshakeRequest(session, protocol.AuthenticateRequest{
+		Username: client.config.Username,
+		Token:    client.config.Token,
+	})
+	return err
+}
+
+// handshakeRequest sends a request and reads its response directly, which is
+// only valid before the reader and writer goroutines of the session start.
+func (client *FileboxClient) handshakeRequest(session *session, data interface{}) (interface{}, error) {
+	message := &protocol.Message{
+		MessageID: atomic.AddUint32(&client.nextMessageID, 1),
+		Data:      data,
+	}
+	if err := session.encoder.Encode(message); err != nil {
+		return nil, err
+	}
+
+	response := &protocol.Message{}
+	if err := session.decoder.Decode(response); err != nil {
+		return nil, err
+	}
+
+	if response.ErrorCode != protocol.ErrorNone {
+		return nil, &protocol.Error{
+			Code:    response.ErrorCode,
+			Message: response.ErrorMessage,
+		}
+	}
+
+	return response.Data, nil
+}
+
+// currentSession returns the current connection, and waits if the client
+// is reconnecting.
+func (client *FileboxClient) currentSession(ctx context.Context) (*session, error) {
+	for {
+		client.mutex.Lock()
+		session, ready, closed := client.session, client.ready, client.closed
+		client.mutex.Unlock()
+
+		if closed {
+			return nil, ErrClosed
+		} else if session != nil {
+			return session, nil
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, contextError(ctx)
+		}
+	}
+}
+
+// RequestName returns the name of a request type, without the "Request"
+// suffix. For example, the name of ReadFileRequest is "ReadFile".
+func RequestName(request interface{}) string {
+	return strings.TrimSuffix(reflect.TypeOf(request).Name(), "Request")
+}
+
+// IsRequestName returns true if name is the RequestName of a request.
+func IsRequestName(name string) bool {
+	for _, requestName := range protocol.RequestNames() {
+		if requestName == name+"Request" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// timeout returns how long to wait for the response to a request.
+func (client *FileboxClient) timeout(request interface{}) time.Duration {
+	if timeout, ok := client.config.Timeouts[RequestName(request)]; ok {
+		return timeout
+	}
+
+	if client.config.Timeout > 0 {
+		return client.config.Timeout
+	}
+
+	return DefaultTimeout
+}
+
+// contextError converts the error of a context that is done to the error
+// that SendReceive returns.
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+
+	return ctx.Err()
+}
+
+// SendReceive sends a request to the server and waits for its response, until
+// ctx is done or the request's timeout expires. If the server fails to handle
+// the request, the returned error is a *protocol.Error. If the timeout
+// expires, the returned error is ErrTimeout.
+//
+// If the connection is lost before the response arrives, the request is sent
+// again after reconnecting. Note that the server might have handled the
+// request already, so requests such as CreateDirectory can fail on the retry.
+func (client *FileboxClient) SendReceive(ctx context.Context, data interface{}) (interface{}, error) {
+	if request, ok := data.(protocol.CloseFileRequest); ok {
+		defer client.handles.remove(request.FileHandle)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, client.timeout(data))
+	defer cancel()
+
+	select {
+	case client.inFlight <- struct{}{}:
+		defer func() { <-client.inFlight }()
+	case <-ctx.Done():
+		return nil, contextError(ctx)
+	}
+
+	for {
+		session, err := client.currentSession(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		request, err := client.handles.toRemote(data)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := client.sendReceive(ctx, session, request)
+		if err == errConnectionLost {
+			log.WithError(err).Warn("Retrying request after reconnecting")
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return client.handles.update(data, response), nil
+	}
+}
+
+// sendReceive sends a request on a specific connection and waits for its response.
+func (client *FileboxClient) sendReceive(ctx context.Context, session *session, data interface{}) (interface{}, error) {
 	// Calculate message ID atomically
 	messageID := atomic.AddUint32(&client.nextMessageID, 1)
 
-	// Create the response channel
-	responseChannel := make(chan *protocol.Message)
+	// Create the response channel. It's buffered, so a late response
+	// never blocks handleMessages.
+	responseChannel := make(chan *protocol.Message, 1)
 	client.channels.Store(messageID, responseChannel)
 	defer client.channels.Delete(messageID)
 
@@ -52,33 +401,70 @@ func (client *FileboxClient) SendReceive(data interface{}) (interface{}, bool) {
 		IsResponse: false,
 		Data:       data,
 	}
-	if err := client.encoder.Encode(message); err != nil {
-		return nil, false
+	select {
+	case session.queue <- message:
+	case <-session.lost:
+		return nil, errConnectionLost
+	case <-ctx.Done():
+		return nil, contextError(ctx)
 	}
 
 	// Wait for response
 	select {
 	case response := <-responseChannel:
-		return response.Data, response.Success
+		if response.ErrorCode != protocol.ErrorNone {
+			return nil, &protocol.Error{
+				Code:    response.ErrorCode,
+				Message: response.ErrorMessage,
+			}
+		}
+
+		return response.Data, nil
 
-	case <-time.After(3 * time.Second):
-		return nil, false
+	case <-session.lost:
+		return nil, errConnectionLost
+
+	case <-ctx.Done():
+		log.WithFields(log.Fields{
+			"MessageID": messageID,
+			"request":   RequestName(data),
+		}).Warn("Request didn't complete in time")
+		return nil, contextError(ctx)
 	}
 }
 
-func (client *FileboxClient) handleMessages(exit chan struct{}) {
+// writeMessages encodes the queued requests of a session until the connection
+// is lost.
+func (client *FileboxClient) writeMessages(session *session) {
+	for {
+		select {
+		case message := <-session.queue:
+			if err := session.encoder.Encode(message); err != nil {
+				log.WithError(err).Error("encoder.Encode() failed")
+
+				// Make handleMessages fail too, so the client reconnects.
+				session.connection.Close()
+				return
+			}
+
+		case <-session.lost:
+			return
+		}
+	}
+}
+
+func (client *FileboxClient) handleMessages(session *session) {
 	for {
 		message := &protocol.Message{}
-		if err := client.decoder.Decode(message); err != nil {
+		if err := session.decoder.Decode(message); err != nil {
 			log.WithError(err).Error("decoder.Decode() failed")
-			close(exit)
+			client.connectionLost(session)
 			return
 		}
 
 		if !message.IsResponse {
-			log.Error("Got a message from the server with IsResponse flag turned off. Exiting")
-			close(exit)
-			return
+			client.handleNotification(message)
+			continue
 		}
 
 		if channel, ok := client.channels.Load(message.MessageID); ok {
@@ -88,3 +474,124 @@ func (client *FileboxClient) handleMessages(exit chan struct{}) {
 		}
 	}
 }
+
+// connectionLost is called when a connection to the server fails. It starts
+// reconnecting, or closes the client if reconnecting is disabled.
+//
+// Only the current session starts a reconnect loop. A session that is lost
+// while the loop is still setting it up was never installed, and the loop
+// notices that on its own and tries again.
+func (client *FileboxClient) connectionLost(session *session) {
+	session.connection.Close()
+
+	client.mutex.Lock()
+	close(session.lost)
+
+	start := client.session == session && !client.reconnecting
+	if client.session == session {
+		client.session = nil
+		client.ready = make(chan struct{})
+		client.reconnecting = true
+	}
+	client.mutex.Unlock()
+
+	if start {
+		go client.reconnect()
+	}
+}
+
+// reconnect tries to connect to the server again with an exponential backoff.
+// Once connected, it reopens all the open files on the new connection.
+func (client *FileboxClient) reconnect() {
+	backoff := client.config.ReconnectBackoff
+
+	for attempt := 1; attempt <= client.config.ReconnectAttempts; attempt++ {
+		log.WithFields(log.Fields{
+			"attempt": attempt,
+			"backoff": backoff,
+		}).Info("Reconnecting.")
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+
+		session, err := client.dial()
+		if err != nil {
+			log.WithError(err).Warn("Reconnect failed")
+			continue
+		}
+
+		go client.handleMessages(session)
+		go client.writeMessages(session)
+
+		client.handles.reopen(func(path string, flags int) (uint64, error) {
+			request := protocol.OpenFileRequest{
+				Path:  path,
+				Flags: flags,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout(request))
+			defer cancel()
+
+			response, err := client.sendReceive(ctx, session, request)
+			if err != nil {
+				return 0, err
+			}
+
+			return response.(protocol.OpenFileResponse).FileHandle, nil
+		})
+
+		// session.lost is closed under the mutex, so the session is either
+		// lost before it's installed, or connectionLost sees it installed.
+		client.mutex.Lock()
+		select {
+		case <-session.lost:
+			client.mutex.Unlock()
+			log.Warn("Connection was lost while reopening files")
+			continue
+		default:
+		}
+
+		client.session = session
+		client.reconnecting = false
+		close(client.ready)
+		client.mutex.Unlock()
+
+		log.Info("Reconnected.")
+		return
+	}
+
+	log.Error("Giving up on reconnecting to the server")
+
+	client.mutex.Lock()
+	client.closed = true
+	client.reconnecting = false
+	close(client.ready)
+	client.mutex.Unlock()
+
+	close(client.exit)
+}
+
+// SetChangeHandler sets a function that is called when another client changes
+// the shared directory.
+func (client *FileboxClient) SetChangeHandler(handler func(protocol.ChangeNotification)) {
+	client.changeHandler.Store(handler)
+}
+
+func (client *FileboxClient) handleNotification(message *protocol.Message) {
+	notification, ok := message.Data.(protocol.ChangeNotification)
+	if !ok {
+		log.WithField("MessageID", message.MessageID).Warn("Got an unknown message from the server. Ignoring")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"type":     notification.Type,
+		"new_path": notification.NewPath,
+	}).Tracef("Remote change in %s", notification.Path)
+
+	if handler, ok := client.changeHandler.Load().(func(protocol.ChangeNotification)); ok {
+		handler(notification)
+	}
+}
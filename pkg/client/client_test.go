@@ -0,0 +1,219 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/alongubkin/filebox/pkg/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// These tests send requests from many goroutines at once, like FUSE does, so
+// run them with -race to check the request queue and the writer goroutine.
+
+// testServer is a server on a free port, which serves a temporary directory.
+type testServer struct {
+	listener  *trackingListener
+	address   string
+	directory string
+}
+
+// startServer runs a server on a free port. Stop it with testServer.stop.
+func startServer(t *testing.T) *testServer {
+	log.SetLevel(log.FatalLevel)
+	protocol.Init()
+
+	directory, err := ioutil.TempDir("", "filebox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		os.RemoveAll(directory)
+		t.Fatal(err)
+	}
+
+	tracking := &trackingListener{Listener: listener}
+	go server.Serve(tracking, server.Config{BasePath: directory})
+
+	return &testServer{listener: tracking, address: listener.Addr().String(), directory: directory}
+}
+
+// stop closes the server's listener and the connections that it accepted,
+// and removes its directory.
+func (s *testServer) stop() {
+	s.listener.Close()
+	s.listener.closeConnections()
+	os.RemoveAll(s.directory)
+}
+
+// trackingListener remembers the connections that it accepts, so they can be
+// closed when the server stops.
+type trackingListener struct {
+	net.Listener
+	mutex       sync.Mutex
+	connections []net.Conn
+}
+
+func (listener *trackingListener) Accept() (net.Conn, error) {
+	connection, err := listener.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+
+	listener.connections = append(listener.connections, connection)
+	return connection, nil
+}
+
+func (listener *trackingListener) closeConnections() {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+
+	for _, connection := range listener.connections {
+		connection.Close()
+	}
+	listener.connections = nil
+}
+
+// writeAndRead writes a file and reads it back through the client, so
+// responses that reach the wrong request are noticed.
+func writeAndRead(client *FileboxClient, name string, data []byte) error {
+	ctx := context.Background()
+
+	response, err := client.SendReceive(ctx, protocol.OpenFileRequest{Path: name, Flags: os.O_CREATE | os.O_RDWR})
+	if err != nil {
+		return err
+	}
+	fileHandle := response.(protocol.OpenFileResponse).FileHandle
+	defer client.SendReceive(ctx, protocol.CloseFileRequest{FileHandle: fileHandle})
+
+	if _, err := client.SendReceive(ctx, protocol.WriteFileRequest{FileHandle: fileHandle, Data: data}); err != nil {
+		return err
+	}
+
+	response, err = client.SendReceive(ctx, protocol.ReadFileRequest{FileHandle: fileHandle, Size: len(data)})
+	if err != nil {
+		return err
+	}
+
+	read := response.(protocol.ReadFileResponse)
+	if !bytes.Equal(read.Data[:read.BytesRead], data) {
+		return fmt.Errorf("%s: read %q, wrote %q", name, read.Data[:read.BytesRead], data)
+	}
+
+	_, err = client.SendReceive(ctx, protocol.GetFileAttributesRequest{Path: name, FileHandle: noFileHandle})
+	return err
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	client, err := Connect(Config{Address: srv.address, Timeout: 10 * time.Second, MaxInFlight: 8}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wait sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			for j := 0; j < 20; j++ {
+				name := fmt.Sprintf("/file-%d", i)
+				if err := writeAndRead(client, name, []byte(fmt.Sprintf("%d-%d", i, j))); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	wait.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentRequestsWhileReconnecting(t *testing.T) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	// Connect through a proxy that drops the connection every few
+	// milliseconds, so requests are queued while sessions are replaced.
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			in, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			out, err := net.Dial("tcp4", srv.address)
+			if err != nil {
+				in.Close()
+				continue
+			}
+
+			go io.Copy(in, out)
+			go io.Copy(out, in)
+			go func() {
+				time.Sleep(30 * time.Millisecond)
+				in.Close()
+				out.Close()
+			}()
+		}
+	}()
+
+	client, err := Connect(Config{
+		Address:           listener.Addr().String(),
+		Timeout:           10 * time.Second,
+		ReconnectAttempts: 1000,
+		ReconnectBackoff:  time.Millisecond,
+	}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wait sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			for j := 0; j < 200; j++ {
+				_, err := client.SendReceive(context.Background(), protocol.GetFileAttributesRequest{Path: "/", FileHandle: noFileHandle})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	wait.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
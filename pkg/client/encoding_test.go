@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// fileOperations sends the requests of test_file_operations in
+// test/test_protobuf.py through a client that uses the encoding, and returns
+// their results and the files that they left in the server's directory.
+func fileOperations(t *testing.T, encoding protocol.Encoding) (results []interface{}, files map[string]string) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	client, err := Connect(Config{Address: srv.address, Timeout: 10 * time.Second, Encoding: encoding}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(request interface{}) interface{} {
+		response, err := client.SendReceive(context.Background(), request)
+
+		// Error messages can contain paths in the server's directory, which
+		// is different for every server.
+		var protocolError *protocol.Error
+		if errors.As(err, &protocolError) {
+			results = append(results, protocolError.Code)
+			return nil
+		} else if err != nil {
+			t.Fatalf("%T failed: %v", request, err)
+		}
+
+		// Change times can't be set, so they're different for every server.
+		switch value := response.(type) {
+		case protocol.GetFileAttributesResponse:
+			value.FileInfo.ChangeTime = time.Time{}
+			response = value
+		case protocol.ReadDirectoryResponse:
+			for i := range value.Files {
+				value.Files[i].ChangeTime = time.Time{}
+			}
+		}
+
+		results = append(results, response)
+		return response
+	}
+
+	send(protocol.CreateDirectoryRequest{Path: "/pb", Mode: 0755})
+	opened := send(protocol.OpenFileRequest{Path: "/pb/file.txt", Flags: os.O_CREATE | os.O_RDWR}).(protocol.OpenFileResponse)
+	send(protocol.WriteFileRequest{FileHandle: opened.FileHandle, Data: []byte("hello protobuf")})
+	send(protocol.FsyncFileRequest{FileHandle: opened.FileHandle})
+	send(protocol.FsyncDirectoryRequest{Path: "/pb"})
+	send(protocol.ReadFileRequest{FileHandle: opened.FileHandle, Offset: 6, Size: 100})
+	send(protocol.TruncateRequest{FileHandle: opened.FileHandle, Size: 5})
+	send(protocol.CloseFileRequest{FileHandle: opened.FileHandle})
+	send(protocol.ChangeModeRequest{Path: "/pb/file.txt", Mode: 0640})
+	send(protocol.ChangeTimesRequest{Path: "/pb/file.txt", AccessTime: time.Unix(1000, 0), ModTime: time.Unix(2000, 0)})
+	send(protocol.GetFileAttributesRequest{Path: "/pb/file.txt", FileHandle: noFileHandle})
+	send(protocol.CreateSymlinkRequest{Path: "/pb/link", Target: "file.txt"})
+	send(protocol.ReadSymlinkRequest{Path: "/pb/link"})
+	send(protocol.CreateHardLinkRequest{OldPath: "/pb/file.txt", NewPath: "/pb/hard"})
+	send(protocol.RenameRequest{OldPath: "/pb/file.txt", NewPath: "/pb/renamed.txt"})
+	send(protocol.DeleteFileRequest{Path: "/pb/link"})
+	send(protocol.ReadDirectoryRequest{Path: "/pb"})
+	send(protocol.GetFileAttributesRequest{Path: "/missing", FileHandle: noFileHandle})
+
+	files = make(map[string]string)
+	err = filepath.Walk(srv.directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == srv.directory {
+			return err
+		}
+
+		name, _ := filepath.Rel(srv.directory, path)
+		files[name] = info.Mode().String()
+		if info.Mode().IsRegular() {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[name] += " " + string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return results, files
+}
+
+// TestEncodings checks that the requests have the same results over both
+// encodings.
+func TestEncodings(t *testing.T) {
+	gobResults, gobFiles := fileOperations(t, protocol.EncodingGob)
+	protobufResults, protobufFiles := fileOperations(t, protocol.EncodingProtobuf)
+
+	if len(gobResults) != len(protobufResults) {
+		t.Fatalf("%d results over gob, %d over protobuf", len(gobResults), len(protobufResults))
+	}
+
+	for i := range gobResults {
+		if !reflect.DeepEqual(gobResults[i], protobufResults[i]) {
+			t.Errorf("result %d:\ngob      %+v\nprotobuf %+v", i, gobResults[i], protobufResults[i])
+		}
+	}
+
+	if !reflect.DeepEqual(gobFiles, protobufFiles) {
+		t.Errorf("the files are different:\ngob      %v\nprotobuf %v", gobFiles, protobufFiles)
+	}
+}
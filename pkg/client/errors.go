@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/billziss-gh/cgofuse/fuse"
+)
+
+// fuseErrors maps protocol error codes to FUSE error numbers.
+var fuseErrors = map[protocol.ErrorCode]int{
+	protocol.ErrorNotFound:      fuse.ENOENT,
+	protocol.ErrorPermission:    fuse.EACCES,
+	protocol.ErrorExists:        fuse.EEXIST,
+	protocol.ErrorNotEmpty:      fuse.ENOTEMPTY,
+	protocol.ErrorIsDirectory:   fuse.EISDIR,
+	protocol.ErrorNotDirectory:  fuse.ENOTDIR,
+	protocol.ErrorInvalid:       fuse.EINVAL,
+	protocol.ErrorBadFileHandle: fuse.EBADF,
+	protocol.ErrorNoSpace:       fuse.ENOSPC,
+	protocol.ErrorNameTooLong:   fuse.ENAMETOOLONG,
+	protocol.ErrorBusy:          fuse.EBUSY,
+	protocol.ErrorCrossDevice:   fuse.EXDEV,
+	protocol.ErrorLoop:          fuse.ELOOP,
+	protocol.ErrorNotSupported:  fuse.ENOTSUP,
+	protocol.ErrorReadOnly:      fuse.EROFS,
+	protocol.ErrorNoAttribute:   fuse.ENOATTR,
+	protocol.ErrorLocked:        fuse.EAGAIN,
+	protocol.ErrorQuotaExceeded: errorQuotaExceeded,
+}
+
+// errno converts an error returned by SendReceive to a negative FUSE error
+// number, which can be returned from the FUSE callbacks.
+func errno(err error) int {
+	switch {
+	case errors.Is(err, ErrTimeout):
+		return -fuse.ETIMEDOUT
+	case errors.Is(err, context.Canceled):
+		return -fuse.EINTR
+	case errors.Is(err, ErrClosed):
+		return -fuse.ENOTCONN
+	}
+
+	var protocolError *protocol.Error
+	if errors.As(err, &protocolError) {
+		if code, ok := fuseErrors[protocolError.Code]; ok {
+			return -code
+		}
+	}
+
+	return -fuse.EIO
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import "syscall"
+
+// errorQuotaExceeded is the FUSE error number of ErrorQuotaExceeded. cgofuse
+// doesn't define EDQUOT, so it's taken from the operating system.
+const errorQuotaExceeded = int(syscall.EDQUOT)
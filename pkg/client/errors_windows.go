@@ -0,0 +1,7 @@
+package client
+
+import "github.com/billziss-gh/cgofuse/fuse"
+
+// errorQuotaExceeded is the FUSE error number of ErrorQuotaExceeded. WinFsp
+// uses the C runtime's error numbers, which have no EDQUOT.
+const errorQuotaExceeded = fuse.ENOSPC
@@ -1,7 +1,10 @@
 package client
 
 import (
+	"context"
+	"os"
 	"runtime"
+	"time"
 
 	"github.com/alongubkin/filebox/pkg/protocol"
 	"github.com/billziss-gh/cgofuse/fuse"
@@ -11,21 +14,35 @@ import (
 type FileboxFileSystem struct {
 	fuse.FileSystemBase
 	Client *FileboxClient
+	Cache  *MetadataCache // nil disables caching
+	Pages  *PageCache
+}
+
+// HandleChange is called when another client changes the shared directory.
+func (fs *FileboxFileSystem) HandleChange(notification protocol.ChangeNotification) {
+	fs.Cache.HandleChange(notification)
+	fs.Pages.InvalidatePath(notification.Path)
 }
 
 // Open opens a file.
 // The flags are a combination of the fuse.O_* constants.
 func (fs *FileboxFileSystem) Open(path string, flags int) (errc int, fh uint64) {
-	response, ok := fs.Client.SendReceive(protocol.OpenFileRequest{
+	response, err := fs.Client.SendReceive(context.Background(), protocol.OpenFileRequest{
 		Path:  path,
 		Flags: flags,
 	})
 
-	if !ok {
-		log.WithField("path", path).Error("OpenFile failed")
-		return -fuse.ENOENT, ^uint64(0)
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("OpenFile failed")
+		return errno(err), ^uint64(0)
 	}
 
+	if flags&(fuse.O_CREAT|fuse.O_TRUNC) != 0 {
+		fs.Cache.Invalidate(path)
+	}
+
+	fs.Pages.Open(response.(protocol.OpenFileResponse).FileHandle, path)
+
 	log.WithFields(log.Fields{
 		"fh":    response.(protocol.OpenFileResponse).FileHandle,
 		"flags": flags,
@@ -38,17 +55,30 @@ func (fs *FileboxFileSystem) Open(path string, flags int) (errc int, fh uint64)
 func (fs *FileboxFileSystem) Getattr(path string, stat *fuse.Stat_t, fh uint64) (errc int) {
 	log.Tracef("Get file attributes %s", path)
 
-	response, ok := fs.Client.SendReceive(protocol.GetFileAttributesRequest{
+	if fileInfo, ok := fs.Cache.GetAttributes(path); ok {
+		*stat = *convertFileInfo(&fileInfo)
+		return 0
+	}
+
+	// The size and the modification time depend on the buffered writes.
+	if err := fs.Pages.FlushPath(context.Background(), path); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	response, err := fs.Client.SendReceive(context.Background(), protocol.GetFileAttributesRequest{
 		Path:       path,
 		FileHandle: fh,
 	})
 
-	if !ok {
-		log.WithField("path", path).Warn("GetFileAttributes failed")
-		return -fuse.ENOENT
+	if err != nil {
+		log.WithField("path", path).WithError(err).Warn("GetFileAttributes failed")
+		return errno(err)
 	}
 
 	fileInfo := response.(protocol.GetFileAttributesResponse).FileInfo
+	fs.Cache.PutAttributes(path, fileInfo)
+
 	*stat = *convertFileInfo(&fileInfo)
 	return 0
 }
@@ -60,19 +90,13 @@ func (fs *FileboxFileSystem) Read(path string, buff []byte, ofst int64, fh uint6
 		"size":   len(buff),
 	}).Tracef("Reading file %s", path)
 
-	response, ok := fs.Client.SendReceive(protocol.ReadFileRequest{
-		FileHandle: fh,
-		Offset:     ofst,
-		Size:       len(buff),
-	})
-
-	if !ok {
-		log.WithField("path", path).Error("ReadFile failed")
-		return 0
+	bytesRead, err := fs.Pages.Read(context.Background(), fh, buff, ofst)
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("ReadFile failed")
+		return errno(err)
 	}
 
-	copy(buff, response.(protocol.ReadFileResponse).Data)
-	return response.(protocol.ReadFileResponse).BytesRead
+	return bytesRead
 }
 
 // Readdir reads a directory.
@@ -86,16 +110,18 @@ func (fs *FileboxFileSystem) Readdir(path string,
 	fill(".", nil, 0)
 	fill("..", nil, 0)
 
-	response, ok := fs.Client.SendReceive(protocol.ReadDirectoryRequest{
-		Path: path,
-	})
-
+	files, ok := fs.Cache.GetDirectory(path)
 	if !ok {
-		log.WithField("path", path).Error("ReadDirectory failed")
-		return -fuse.ENOENT
+		var err error
+		if files, err = fs.readDirectory(path); err != nil {
+			log.WithField("path", path).WithError(err).Error("ReadDirectory failed")
+			return errno(err)
+		}
+
+		fs.Cache.PutDirectory(path, files)
 	}
 
-	for _, file := range response.(protocol.ReadDirectoryResponse).Files {
+	for _, file := range files {
 		if !fill(file.Name, convertFileInfo(&file), 0) {
 			break
 		}
@@ -104,12 +130,91 @@ func (fs *FileboxFileSystem) Readdir(path string,
 	return 0
 }
 
+// readDirectory lists a directory page by page, so large directories don't
+// exceed the message size limit. Servers that don't read directories in pages
+// return all the files in the first response.
+func (fs *FileboxFileSystem) readDirectory(path string) ([]protocol.FileInfo, error) {
+	var files []protocol.FileInfo
+	request := protocol.ReadDirectoryRequest{Path: path, Limit: protocol.MaxDirectoryPage}
+
+	for {
+		response, err := fs.Client.SendReceive(context.Background(), request)
+		if err != nil {
+			return nil, err
+		}
+
+		page := response.(protocol.ReadDirectoryResponse)
+		files = append(files, page.Files...)
+		if !page.More || len(page.Files) == 0 {
+			return files, nil
+		}
+
+		request.After = page.Files[len(page.Files)-1].Name
+	}
+}
+
+// Flush is called when a file descriptor of an open file is closed. It writes
+// the buffered data of the file, so errors are reported to close(2).
+func (fs *FileboxFileSystem) Flush(path string, fh uint64) int {
+	log.WithField("fh", fh).Tracef("Flushing file %s", path)
+
+	if err := fs.Pages.Flush(context.Background(), fh); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Fsync writes the buffered data of a file, and waits until the server has
+// written the file to its disk.
+func (fs *FileboxFileSystem) Fsync(path string, datasync bool, fh uint64) int {
+	log.WithField("fh", fh).Tracef("Syncing file %s", path)
+
+	if err := fs.Pages.Flush(context.Background(), fh); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.FsyncFileRequest{
+		FileHandle: fh,
+	})
+
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("FsyncFile failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Fsyncdir waits until the server has written the entries of a directory to
+// its disk.
+func (fs *FileboxFileSystem) Fsyncdir(path string, datasync bool, fh uint64) int {
+	log.Tracef("Syncing directory %s", path)
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.FsyncDirectoryRequest{
+		Path: path,
+	})
+
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("FsyncDirectory failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
 // Release closes an open file.
 func (fs *FileboxFileSystem) Release(path string, fh uint64) int {
 	log.WithField("fh", fh).Tracef("Closing file %s", path)
 
-	if _, ok := fs.Client.SendReceive(protocol.CloseFileRequest{fh}); !ok {
-		log.WithField("path", path).Error("CloseFile failed")
+	if err := fs.Pages.Release(context.Background(), fh); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+	}
+
+	if _, err := fs.Client.SendReceive(context.Background(), protocol.CloseFileRequest{fh}); err != nil {
+		log.WithField("path", path).WithError(err).Error("CloseFile failed")
 	}
 
 	return 0
@@ -119,9 +224,11 @@ func (fs *FileboxFileSystem) Release(path string, fh uint64) int {
 func (fs *FileboxFileSystem) Mkdir(path string, mode uint32) int {
 	log.Tracef("Creating directory %s", path)
 
-	if _, ok := fs.Client.SendReceive(protocol.CreateDirectoryRequest{path, mode}); !ok {
-		log.WithField("path", path).Error("CreateDirectory failed")
-		return -fuse.EIO
+	defer fs.Cache.Invalidate(path)
+
+	if _, err := fs.Client.SendReceive(context.Background(), protocol.CreateDirectoryRequest{path, mode}); err != nil {
+		log.WithField("path", path).WithError(err).Error("CreateDirectory failed")
+		return errno(err)
 	}
 
 	return 0
@@ -137,12 +244,14 @@ func (fs *FileboxFileSystem) Mknod(path string, mode uint32, dev uint64) int {
 			"mode": mode,
 			"dev":  dev,
 		}).Errorf("Invalid file mode. ")
-		return fuse.EINVAL
+		return -fuse.EINVAL
 	}
 
-	if _, ok := fs.Client.SendReceive(protocol.CreateFileRequest{path}); !ok {
-		log.WithField("path", path).Error("CreateFile failed")
-		return -fuse.EIO
+	defer fs.Cache.Invalidate(path)
+
+	if _, err := fs.Client.SendReceive(context.Background(), protocol.CreateFileRequest{path}); err != nil {
+		log.WithField("path", path).WithError(err).Error("CreateFile failed")
+		return errno(err)
 	}
 
 	return 0
@@ -152,16 +261,291 @@ func (fs *FileboxFileSystem) Mknod(path string, mode uint32, dev uint64) int {
 func (fs *FileboxFileSystem) Rename(oldpath string, newpath string) int {
 	log.Tracef("Renaming %s to %s", oldpath, newpath)
 
-	_, ok := fs.Client.SendReceive(protocol.RenameRequest{
+	defer fs.Cache.InvalidateTree(oldpath)
+	defer fs.Cache.InvalidateTree(newpath)
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.RenameRequest{
 		OldPath: oldpath,
 		NewPath: newpath,
 	})
 
-	if !ok {
+	if err != nil {
+		log.WithFields(log.Fields{
+			"oldpath": oldpath,
+			"newpath": newpath,
+		}).WithError(err).Error("Rename failed")
+		return errno(err)
+	}
+
+	fs.Pages.Rename(oldpath, newpath)
+	return 0
+}
+
+// Symlink creates a symbolic link.
+func (fs *FileboxFileSystem) Symlink(target string, newpath string) int {
+	log.WithField("target", target).Tracef("Creating symbolic link %s", newpath)
+
+	defer fs.Cache.Invalidate(newpath)
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.CreateSymlinkRequest{
+		Path:   newpath,
+		Target: target,
+	})
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"path":   newpath,
+			"target": target,
+		}).WithError(err).Error("CreateSymlink failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Readlink reads the target of a symbolic link.
+func (fs *FileboxFileSystem) Readlink(path string) (int, string) {
+	log.Tracef("Reading symbolic link %s", path)
+
+	response, err := fs.Client.SendReceive(context.Background(), protocol.ReadSymlinkRequest{
+		Path: path,
+	})
+
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("ReadSymlink failed")
+		return errno(err), ""
+	}
+
+	return 0, response.(protocol.ReadSymlinkResponse).Target
+}
+
+// Link creates a hard link to a file.
+func (fs *FileboxFileSystem) Link(oldpath string, newpath string) int {
+	log.Tracef("Linking %s to %s", newpath, oldpath)
+
+	// The number of links of the file changes too.
+	defer fs.Cache.Invalidate(oldpath)
+	defer fs.Cache.Invalidate(newpath)
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.CreateHardLinkRequest{
+		OldPath: oldpath,
+		NewPath: newpath,
+	})
+
+	if err != nil {
 		log.WithFields(log.Fields{
 			"oldpath": oldpath,
 			"newpath": newpath,
-		}).Error("Rename failed")
+		}).WithError(err).Error("CreateHardLink failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Getxattr reads an extended attribute of a file.
+func (fs *FileboxFileSystem) Getxattr(path string, name string) (int, []byte) {
+	log.WithField("name", name).Tracef("Getting extended attribute of %s", path)
+
+	response, err := fs.Client.SendReceive(context.Background(), protocol.GetExtendedAttributeRequest{
+		Path: path,
+		Name: name,
+	})
+
+	if err != nil {
+		// Missing attributes are common, so they aren't logged as errors.
+		log.WithFields(log.Fields{
+			"path": path,
+			"name": name,
+		}).WithError(err).Trace("GetExtendedAttribute failed")
+		return errno(err), nil
+	}
+
+	return 0, response.(protocol.GetExtendedAttributeResponse).Value
+}
+
+// Listxattr lists the extended attributes of a file.
+func (fs *FileboxFileSystem) Listxattr(path string, fill func(name string) bool) int {
+	log.Tracef("Listing extended attributes of %s", path)
+
+	response, err := fs.Client.SendReceive(context.Background(), protocol.ListExtendedAttributesRequest{
+		Path: path,
+	})
+
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("ListExtendedAttributes failed")
+		return errno(err)
+	}
+
+	for _, name := range response.(protocol.ListExtendedAttributesResponse).Names {
+		if !fill(name) {
+			return -fuse.ERANGE
+		}
+	}
+
+	return 0
+}
+
+// Setxattr sets an extended attribute of a file.
+func (fs *FileboxFileSystem) Setxattr(path string, name string, value []byte, flags int) int {
+	log.WithField("name", name).Tracef("Setting extended attribute of %s", path)
+
+	request := protocol.SetExtendedAttributeRequest{
+		Path:  path,
+		Name:  name,
+		Value: value,
+	}
+
+	if flags&fuse.XATTR_CREATE != 0 {
+		request.Flags |= protocol.XattrCreate
+	}
+
+	if flags&fuse.XATTR_REPLACE != 0 {
+		request.Flags |= protocol.XattrReplace
+	}
+
+	if _, err := fs.Client.SendReceive(context.Background(), request); err != nil {
+		log.WithFields(log.Fields{
+			"path": path,
+			"name": name,
+		}).WithError(err).Error("SetExtendedAttribute failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Removexattr removes an extended attribute of a file.
+func (fs *FileboxFileSystem) Removexattr(path string, name string) int {
+	log.WithField("name", name).Tracef("Removing extended attribute of %s", path)
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.RemoveExtendedAttributeRequest{
+		Path: path,
+		Name: name,
+	})
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"path": path,
+			"name": name,
+		}).WithError(err).Error("RemoveExtendedAttribute failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Chmod changes the permission bits of a file.
+func (fs *FileboxFileSystem) Chmod(path string, mode uint32) int {
+	log.WithField("mode", mode).Tracef("Changing mode of %s", path)
+
+	defer fs.Cache.Invalidate(path)
+
+	// Buffered writes must reach the file while it's still writable.
+	if err := fs.Pages.FlushPath(context.Background(), path); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.ChangeModeRequest{
+		Path: path,
+		Mode: mode & 07777,
+	})
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"path": path,
+			"mode": mode,
+		}).WithError(err).Error("ChangeMode failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// Chown changes the owner and the group of a file.
+func (fs *FileboxFileSystem) Chown(path string, uid uint32, gid uint32) int {
+	log.WithFields(log.Fields{
+		"uid": uid,
+		"gid": gid,
+	}).Tracef("Changing owner of %s", path)
+
+	defer fs.Cache.Invalidate(path)
+
+	// Buffered writes must reach the file before it changes hands.
+	if err := fs.Pages.FlushPath(context.Background(), path); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	// FUSE passes -1 as an unsigned number to keep the owner or the group.
+	_, err := fs.Client.SendReceive(context.Background(), protocol.ChangeOwnerRequest{
+		Path: path,
+		Uid:  int(int32(uid)),
+		Gid:  int(int32(gid)),
+	})
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"path": path,
+			"uid":  uid,
+			"gid":  gid,
+		}).WithError(err).Error("ChangeOwner failed")
+		return errno(err)
+	}
+
+	return 0
+}
+
+// The UTIME_NOW and UTIME_OMIT markers of utimensat(2), which the FUSE
+// library passes on in the nanoseconds of a time. Linux uses the first pair,
+// and macOS and the BSDs the second one. Neither is a valid number of
+// nanoseconds, so they're checked on every platform.
+const (
+	utimeNowLinux  = 1<<30 - 1
+	utimeOmitLinux = 1<<30 - 2
+	utimeNow       = -1
+	utimeOmit      = -2
+)
+
+// changeTime converts a time that Utimens got to the time in a
+// ChangeTimesRequest, where the zero time keeps the file's current time.
+func changeTime(tmsp fuse.Timespec) time.Time {
+	switch tmsp.Nsec {
+	case utimeNowLinux, utimeNow:
+		return time.Now()
+	case utimeOmitLinux, utimeOmit:
+		return time.Time{}
+	}
+
+	return tmsp.Time()
+}
+
+// Utimens changes the access and modification times of a file. If tmsp is
+// nil, both are set to the current time.
+func (fs *FileboxFileSystem) Utimens(path string, tmsp []fuse.Timespec) int {
+	log.Tracef("Changing times of %s", path)
+
+	defer fs.Cache.Invalidate(path)
+
+	// Buffered writes that are sent later would change the modification time.
+	if err := fs.Pages.FlushPath(context.Background(), path); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	request := protocol.ChangeTimesRequest{Path: path}
+	if tmsp == nil {
+		request.AccessTime = time.Now()
+		request.ModTime = request.AccessTime
+	} else {
+		request.AccessTime = changeTime(tmsp[0])
+		request.ModTime = changeTime(tmsp[1])
+	}
+
+	if _, err := fs.Client.SendReceive(context.Background(), request); err != nil {
+		log.WithField("path", path).WithError(err).Error("ChangeTimes failed")
+		return errno(err)
 	}
 
 	return 0
@@ -171,9 +555,11 @@ func (fs *FileboxFileSystem) Rename(oldpath string, newpath string) int {
 func (fs *FileboxFileSystem) Rmdir(path string) int {
 	log.Tracef("Deleting directory %s", path)
 
-	if _, ok := fs.Client.SendReceive(protocol.DeleteDirectoryRequest{path}); !ok {
-		log.WithField("path", path).Error("DeleteDirectory failed")
-		return -fuse.EIO
+	defer fs.Cache.InvalidateTree(path)
+
+	if _, err := fs.Client.SendReceive(context.Background(), protocol.DeleteDirectoryRequest{path}); err != nil {
+		log.WithField("path", path).WithError(err).Error("DeleteDirectory failed")
+		return errno(err)
 	}
 
 	return 0
@@ -183,19 +569,28 @@ func (fs *FileboxFileSystem) Rmdir(path string) int {
 func (fs *FileboxFileSystem) Truncate(path string, size int64, fh uint64) int {
 	log.Tracef("Truncating %s", path)
 
-	_, ok := fs.Client.SendReceive(protocol.TruncateRequest{
+	defer fs.Cache.Invalidate(path)
+	defer fs.Pages.InvalidatePath(path)
+
+	// Buffered writes must not extend the file after it's truncated.
+	if err := fs.Pages.FlushPath(context.Background(), path); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	_, err := fs.Client.SendReceive(context.Background(), protocol.TruncateRequest{
 		Path:       path,
 		Size:       size,
 		FileHandle: fh,
 	})
 
-	if !ok {
+	if err != nil {
 		log.WithFields(log.Fields{
 			"path": path,
 			"size": size,
 			"fh":   fh,
-		}).Error("Truncate failed")
-		return -fuse.EIO
+		}).WithError(err).Error("Truncate failed")
+		return errno(err)
 	}
 
 	return 0
@@ -205,9 +600,11 @@ func (fs *FileboxFileSystem) Truncate(path string, size int64, fh uint64) int {
 func (fs *FileboxFileSystem) Unlink(path string) int {
 	log.Tracef("Deleting file %s", path)
 
-	if _, ok := fs.Client.SendReceive(protocol.DeleteFileRequest{path}); !ok {
-		log.WithField("path", path).Error("Truncate failed")
-		return -fuse.EIO
+	defer fs.Cache.Invalidate(path)
+
+	if _, err := fs.Client.SendReceive(context.Background(), protocol.DeleteFileRequest{path}); err != nil {
+		log.WithField("path", path).WithError(err).Error("DeleteFile failed")
+		return errno(err)
 	}
 
 	return 0
@@ -220,32 +617,39 @@ func (fs *FileboxFileSystem) Write(path string, buff []byte, ofst int64, fh uint
 		"size":   len(buff),
 	}).Tracef("Writing file %s", path)
 
-	response, ok := fs.Client.SendReceive(protocol.WriteFileRequest{
-		FileHandle: fh,
-		Data:       buff,
-		Offset:     ofst,
-	})
+	defer fs.Cache.Invalidate(path)
 
-	if !ok {
-		log.WithField("path", path).Error("WriteFile failed")
-		return -fuse.EIO
+	bytesWritten, err := fs.Pages.Write(context.Background(), fh, buff, ofst)
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("WriteFile failed")
+		return errno(err)
 	}
 
-	return response.(protocol.WriteFileResponse).BytesWritten
+	return bytesWritten
 }
 
 func (fs *FileboxFileSystem) Statfs(path string, stat *fuse.Statfs_t) int {
 	log.Tracef("Statfs %s", path)
 
-	const blockSize = 4096
-	const fsBlocks = (1 << 50) / blockSize
-	stat.Blocks = fsBlocks  // Total data blocks in file system.
-	stat.Bfree = fsBlocks   // Free blocks in file system.
-	stat.Bavail = fsBlocks  // Free blocks in file system if you're not root.
-	stat.Files = 1e9        // Total files in file system.
-	stat.Ffree = 1e9        // Free files in file system.
-	stat.Bsize = blockSize  // Block size
-	stat.Frsize = blockSize // Fragment size, smallest addressable data size in the file system.
+	response, err := fs.Client.SendReceive(context.Background(), protocol.StatfsRequest{
+		Path: path,
+	})
+
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("Statfs failed")
+		return errno(err)
+	}
+
+	usage := response.(protocol.StatfsResponse)
+	stat.Blocks = usage.Blocks          // Total data blocks in file system.
+	stat.Bfree = usage.BlocksFree       // Free blocks in file system.
+	stat.Bavail = usage.BlocksAvailable // Free blocks in file system if you're not root.
+	stat.Files = usage.Files            // Total files in file system.
+	stat.Ffree = usage.FilesFree        // Free files in file system.
+	stat.Favail = usage.FilesFree       // Free files in file system if you're not root.
+	stat.Bsize = usage.BlockSize        // Block size
+	stat.Frsize = usage.BlockSize       // Fragment size, smallest addressable data size in the file system.
+	stat.Namemax = usage.NameLength     // Maximum length of a file name.
 
 	clipBlocks(&stat.Blocks)
 	clipBlocks(&stat.Bfree)
@@ -255,7 +659,19 @@ func (fs *FileboxFileSystem) Statfs(path string, stat *fuse.Statfs_t) int {
 }
 
 func convertFileInfo(file *protocol.FileInfo) *fuse.Stat_t {
-	var mode uint32 = 00777
+	mode := uint32(file.Mode.Perm())
+
+	if file.Mode&os.ModeSetuid != 0 {
+		mode |= fuse.S_ISUID
+	}
+
+	if file.Mode&os.ModeSetgid != 0 {
+		mode |= fuse.S_ISGID
+	}
+
+	if file.Mode&os.ModeSticky != 0 {
+		mode |= fuse.S_ISVTX
+	}
 
 	if file.Mode.IsDir() {
 		mode |= fuse.S_IFDIR
@@ -265,10 +681,35 @@ func convertFileInfo(file *protocol.FileInfo) *fuse.Stat_t {
 		mode |= fuse.S_IFREG
 	}
 
+	if file.Mode&os.ModeSymlink != 0 {
+		mode |= fuse.S_IFLNK
+	}
+
+	// Servers that don't send the access and change times only have ModTime.
+	accessTime, changeTime := file.AccessTime, file.ChangeTime
+	if accessTime.IsZero() {
+		accessTime = file.ModTime
+	}
+
+	if changeTime.IsZero() {
+		changeTime = file.ModTime
+	}
+
+	// Servers that don't send the number of links have a single one.
+	nlink := file.Nlink
+	if nlink == 0 {
+		nlink = 1
+	}
+
 	return &fuse.Stat_t{
-		Mode: mode,
-		Size: file.Size,
-		Mtim: fuse.NewTimespec(file.ModTime),
+		Mode:  mode,
+		Nlink: nlink,
+		Uid:   file.Uid,
+		Gid:   file.Gid,
+		Size:  file.Size,
+		Atim:  fuse.NewTimespec(accessTime),
+		Mtim:  fuse.NewTimespec(file.ModTime),
+		Ctim:  fuse.NewTimespec(changeTime),
 	}
 }
 
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/billziss-gh/cgofuse/fuse"
+)
+
+func TestChangeTime(t *testing.T) {
+	moment := time.Date(2019, 9, 1, 12, 0, 0, 500, time.UTC)
+
+	if got := changeTime(fuse.NewTimespec(moment)); !got.Equal(moment) {
+		t.Errorf("changeTime(%v) = %v", moment, got)
+	}
+
+	for _, omit := range []int64{utimeOmitLinux, utimeOmit} {
+		if got := changeTime(fuse.Timespec{Nsec: omit}); !got.IsZero() {
+			t.Errorf("changeTime(UTIME_OMIT %d) = %v, want the zero time", omit, got)
+		}
+	}
+
+	for _, now := range []int64{utimeNowLinux, utimeNow} {
+		if got := changeTime(fuse.Timespec{Nsec: now}); time.Since(got) > time.Minute || time.Since(got) < 0 {
+			t.Errorf("changeTime(UTIME_NOW %d) = %v, want the current time", now, got)
+		}
+	}
+}
+
+func TestReaddirPages(t *testing.T) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	count := 2*protocol.MaxDirectoryPage + 10
+	for i := 0; i < count; i++ {
+		if err := ioutil.WriteFile(filepath.Join(srv.directory, fmt.Sprintf("file-%05d", i)), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := Connect(Config{Address: srv.address, Timeout: 10 * time.Second}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &FileboxFileSystem{Client: client, Pages: NewPageCache(client, 0, 0)}
+
+	var names []string
+	errc := fs.Readdir("/", func(name string, stat *fuse.Stat_t, ofst int64) bool {
+		names = append(names, name)
+		return true
+	}, 0, noFileHandle)
+
+	if errc != 0 {
+		t.Fatalf("Readdir() = %d", errc)
+	}
+
+	// Along with . and ..
+	if len(names) != count+2 || names[2] != "file-00000" || names[len(names)-1] != fmt.Sprintf("file-%05d", count-1) {
+		t.Errorf("Readdir() listed %d files, want %d", len(names)-2, count)
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/billziss-gh/cgofuse/fuse"
+	log "github.com/sirupsen/logrus"
+)
+
+// openFile is a file that was opened through the client. The file handles
+// that FUSE sees are local, and are mapped to the server's file handles, so
+// the files can be reopened on a new connection after a reconnect.
+type openFile struct {
+	path         string
+	flags        int
+	remoteHandle uint64
+	stale        bool // the file couldn't be reopened after a reconnect
+}
+
+// noFileHandle is the file handle that FUSE passes to requests that aren't
+// made through an open file.
+const noFileHandle = ^uint64(0)
+
+type handleTable struct {
+	mutex           sync.Mutex
+	files           map[uint64]*openFile
+	nextLocalHandle uint64
+}
+
+// add registers a file that was opened on the server, and returns its local handle.
+func (table *handleTable) add(path string, flags int, remoteHandle uint64) uint64 {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	if table.files == nil {
+		table.files = make(map[uint64]*openFile)
+	}
+
+	table.nextLocalHandle++
+	table.files[table.nextLocalHandle] = &openFile{path: path, flags: flags, remoteHandle: remoteHandle}
+	return table.nextLocalHandle
+}
+
+func (table *handleTable) remove(localHandle uint64) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	delete(table.files, localHandle)
+}
+
+// remote returns the server's file handle for a local handle. noFileHandle,
+// for requests without a file handle, is returned as is. Handles that the
+// table doesn't know fail with EBADF rather than being sent as they are,
+// since they could be the server's handle of another file.
+func (table *handleTable) remote(localHandle uint64) (uint64, error) {
+	if localHandle == noFileHandle {
+		return localHandle, nil
+	}
+
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	file, ok := table.files[localHandle]
+	if !ok {
+		return 0, &protocol.Error{Code: protocol.ErrorBadFileHandle, Message: "unknown file handle"}
+	}
+
+	if file.stale {
+		return 0, &protocol.Error{Code: protocol.ErrorBadFileHandle, Message: "file couldn't be reopened after reconnecting"}
+	}
+
+	return file.remoteHandle, nil
+}
+
+// rename updates the paths of open files after a successful rename.
+func (table *handleTable) rename(oldPath string, newPath string) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	for _, file := range table.files {
+		if file.path == oldPath {
+			file.path = newPath
+		} else if strings.HasPrefix(file.path, oldPath+"/") {
+			file.path = newPath + strings.TrimPrefix(file.path, oldPath)
+		}
+	}
+}
+
+// reopen opens all the files in the table again with the open function,
+// which is used after reconnecting to the server.
+func (table *handleTable) reopen(open func(path string, flags int) (uint64, error)) {
+	table.mutex.Lock()
+	defer table.mutex.Unlock()
+
+	for localHandle, file := range table.files {
+		// Don't create or truncate the file again.
+		remoteHandle, err := open(file.path, file.flags&^(fuse.O_CREAT|fuse.O_EXCL|fuse.O_TRUNC))
+		if err != nil {
+			log.WithField("fh", localHandle).WithError(err).Warnf("Failed to reopen %s", file.path)
+			file.stale = true
+			continue
+		}
+
+		file.remoteHandle = remoteHandle
+		file.stale = false
+	}
+}
+
+// toRemote replaces the local file handle in a request with the server's file handle.
+func (table *handleTable) toRemote(request interface{}) (interface{}, error) {
+	var err error
+
+	switch request := request.(type) {
+	case protocol.ReadFileRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.WriteFileRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.GetFileAttributesRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.TruncateRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.CloseFileRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.FsyncFileRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.LockRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.UnlockRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+
+	case protocol.TestLockRequest:
+		request.FileHandle, err = table.remote(request.FileHandle)
+		return request, err
+	}
+
+	return request, nil
+}
+
+// update keeps the table in sync with a request that succeeded, and replaces
+// the server's file handle in the response with a local one.
+func (table *handleTable) update(request interface{}, response interface{}) interface{} {
+	switch request := request.(type) {
+	case protocol.OpenFileRequest:
+		openResponse := response.(protocol.OpenFileResponse)
+		openResponse.FileHandle = table.add(request.Path, request.Flags, openResponse.FileHandle)
+		return openResponse
+
+	case protocol.RenameRequest:
+		table.rename(request.OldPath, request.NewPath)
+	}
+
+	return response
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+func TestRemoteHandles(t *testing.T) {
+	var table handleTable
+	localHandle := table.add("/file", 0, 7)
+
+	if remoteHandle, err := table.remote(localHandle); err != nil || remoteHandle != 7 {
+		t.Errorf("remote(%d) = %d, %v, want 7", localHandle, remoteHandle, err)
+	}
+
+	if remoteHandle, err := table.remote(noFileHandle); err != nil || remoteHandle != noFileHandle {
+		t.Errorf("remote(noFileHandle) = %d, %v, want noFileHandle", remoteHandle, err)
+	}
+
+	// A handle that was closed, or that belongs to an earlier connection,
+	// must not reach the server, where it could be another file.
+	table.remove(localHandle)
+	for _, unknown := range []uint64{localHandle, 7, 0} {
+		var protocolError *protocol.Error
+		if _, err := table.remote(unknown); !errors.As(err, &protocolError) || protocolError.Code != protocol.ErrorBadFileHandle {
+			t.Errorf("remote(%d) = %v, want ErrorBadFileHandle", unknown, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/billziss-gh/cgofuse/fuse"
+	log "github.com/sirupsen/logrus"
+)
+
+// Polling intervals of locks that wait for a conflicting lock to be released.
+// The server never blocks a request, so waiting is done by retrying.
+const (
+	minLockInterval = 10 * time.Millisecond
+	maxLockInterval = time.Second
+)
+
+// The FUSE library passes locks to the file system instead of the kernel
+// keeping them locally only if it implements these interfaces.
+var (
+	_ fuse.FileSystemLock  = (*FileboxFileSystem)(nil)
+	_ fuse.FileSystemFlock = (*FileboxFileSystem)(nil)
+)
+
+// Lock gets, places or removes a byte range lock that is shared with the other
+// clients, like fcntl(2). Locks are held by the connection, and are lost if
+// it reconnects.
+func (fs *FileboxFileSystem) Lock(path string, cmd int, lock *fuse.Lock_t, fh uint64, owner uint64) int {
+	log.WithFields(log.Fields{
+		"fh":    fh,
+		"cmd":   cmd,
+		"type":  lock.Type,
+		"start": lock.Start,
+		"len":   lock.Len,
+		"owner": owner,
+	}).Tracef("Locking %s", path)
+
+	// FUSE passes absolute ranges.
+	if lock.Whence != io.SeekStart || lock.Start < 0 || lock.Len < 0 {
+		return -fuse.EINVAL
+	}
+
+	lockType, ok := lockTypes[int(lock.Type)]
+	if !ok {
+		return -fuse.EINVAL
+	}
+
+	switch cmd {
+	case fuse.F_GETLK:
+		if lockType == protocol.LockNone {
+			return -fuse.EINVAL
+		}
+
+		response, err := fs.Client.SendReceive(context.Background(), protocol.TestLockRequest{
+			FileHandle: fh,
+			Kind:       protocol.LockRange,
+			Type:       lockType,
+			Start:      lock.Start,
+			Length:     lock.Len,
+			Owner:      owner,
+		})
+
+		if err != nil {
+			log.WithField("path", path).WithError(err).Error("TestLock failed")
+			return errno(err)
+		}
+
+		// The owner of a conflicting lock is another client, so there's no
+		// process to report.
+		conflict := response.(protocol.TestLockResponse)
+		if conflict.Type == protocol.LockNone {
+			lock.Type = int16(fuse.F_UNLCK)
+			return 0
+		}
+
+		if conflict.Type == protocol.LockShared {
+			lock.Type = int16(fuse.F_RDLCK)
+		} else {
+			lock.Type = int16(fuse.F_WRLCK)
+		}
+		lock.Start = conflict.Start
+		lock.Len = conflict.Length
+		lock.Pid = 0
+		return 0
+
+	case fuse.F_SETLK, fuse.F_SETLKW:
+		return fs.lock(path, fh, protocol.LockRange, lockType, lock.Start, lock.Len, owner, cmd == fuse.F_SETLKW)
+	}
+
+	return -fuse.EINVAL
+}
+
+// lockTypes maps the lock types of fcntl(2) to the lock types of the protocol.
+var lockTypes = map[int]protocol.LockType{
+	fuse.F_RDLCK: protocol.LockShared,
+	fuse.F_WRLCK: protocol.LockExclusive,
+	fuse.F_UNLCK: protocol.LockNone,
+}
+
+// Flock places or removes a whole file lock that is shared with the other
+// clients, like flock(2). The lock is owned by the open file. Byte range locks
+// and whole file locks don't conflict with each other.
+func (fs *FileboxFileSystem) Flock(path string, op int, fh uint64, owner uint64) int {
+	log.WithFields(log.Fields{
+		"fh": fh,
+		"op": op,
+	}).Tracef("Locking %s", path)
+
+	wait := op&fuse.LOCK_NB == 0
+	switch op &^ fuse.LOCK_NB {
+	case fuse.LOCK_SH:
+		return fs.lock(path, fh, protocol.LockFile, protocol.LockShared, 0, 0, 0, wait)
+	case fuse.LOCK_EX:
+		return fs.lock(path, fh, protocol.LockFile, protocol.LockExclusive, 0, 0, 0, wait)
+	case fuse.LOCK_UN:
+		return fs.lock(path, fh, protocol.LockFile, protocol.LockNone, 0, 0, 0, false)
+	}
+
+	return -fuse.EINVAL
+}
+
+func (fs *FileboxFileSystem) lock(path string, fh uint64, kind protocol.LockKind, lockType protocol.LockType, start int64, length int64, owner uint64, wait bool) int {
+	// Another client must see the writes that were made under the lock once
+	// it gets the lock.
+	if err := fs.Pages.Flush(context.Background(), fh); err != nil {
+		log.WithField("path", path).WithError(err).Error("Writing buffered data failed")
+		return errno(err)
+	}
+
+	if lockType == protocol.LockNone {
+		_, err := fs.Client.SendReceive(context.Background(), protocol.UnlockRequest{
+			FileHandle: fh,
+			Kind:       kind,
+			Start:      start,
+			Length:     length,
+			Owner:      owner,
+		})
+
+		if err != nil {
+			log.WithField("path", path).WithError(err).Error("Unlock failed")
+			return errno(err)
+		}
+
+		return 0
+	}
+
+	request := protocol.LockRequest{
+		FileHandle: fh,
+		Kind:       kind,
+		Type:       lockType,
+		Start:      start,
+		Length:     length,
+		Owner:      owner,
+	}
+
+	for interval := minLockInterval; ; interval *= 2 {
+		_, err := fs.Client.SendReceive(context.Background(), request)
+		if err == nil {
+			break
+		}
+
+		var protocolError *protocol.Error
+		if !wait || !errors.As(err, &protocolError) || protocolError.Code != protocol.ErrorLocked {
+			if errno(err) != -fuse.EAGAIN {
+				log.WithField("path", path).WithError(err).Error("Lock failed")
+			}
+			return errno(err)
+		}
+
+		if interval > maxLockInterval {
+			interval = maxLockInterval
+		}
+		time.Sleep(interval)
+	}
+
+	// Data that was read before the lock was placed might have been changed
+	// by the previous owner of the lock.
+	fs.Cache.Invalidate(path)
+	fs.Pages.InvalidatePath(path)
+	return 0
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/billziss-gh/cgofuse/fuse"
+)
+
+// mount returns a file system of a new client of the server, like a mounted
+// directory, and a file that it opened.
+func mount(t *testing.T, srv *testServer, path string) (*FileboxFileSystem, uint64) {
+	client, err := Connect(Config{Address: srv.address, Timeout: 10 * time.Second}, make(chan struct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &FileboxFileSystem{Client: client, Pages: NewPageCache(client, 0, 0)}
+	if errc := fs.Mknod(path, fuse.S_IFREG|0644, 0); errc != 0 && errc != -fuse.EEXIST {
+		t.Fatalf("Mknod(%s) = %d", path, errc)
+	}
+
+	errc, fh := fs.Open(path, fuse.O_RDWR)
+	if errc != 0 {
+		t.Fatalf("Open(%s) = %d", path, errc)
+	}
+
+	return fs, fh
+}
+
+func TestLock(t *testing.T) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	first, firstHandle := mount(t, srv, "/file")
+	second, secondHandle := mount(t, srv, "/file")
+
+	if errc := first.Lock("/file", fuse.F_SETLK, &fuse.Lock_t{Type: int16(fuse.F_WRLCK), Start: 10, Len: 10}, firstHandle, 1); errc != 0 {
+		t.Fatalf("F_SETLK = %d", errc)
+	}
+
+	// The same owner on the other client is another process.
+	if errc := second.Lock("/file", fuse.F_SETLK, &fuse.Lock_t{Type: int16(fuse.F_RDLCK), Start: 15, Len: 1}, secondHandle, 1); errc != -fuse.EAGAIN {
+		t.Errorf("F_SETLK of a conflicting lock = %d, want EAGAIN", errc)
+	}
+
+	lock := fuse.Lock_t{Type: int16(fuse.F_RDLCK), Start: 0, Len: 0, Pid: 1234}
+	if errc := second.Lock("/file", fuse.F_GETLK, &lock, secondHandle, 1); errc != 0 {
+		t.Fatalf("F_GETLK = %d", errc)
+	}
+	if lock != (fuse.Lock_t{Type: int16(fuse.F_WRLCK), Start: 10, Len: 10}) {
+		t.Errorf("F_GETLK = %+v, want the write lock of the first client", lock)
+	}
+
+	lock = fuse.Lock_t{Type: int16(fuse.F_WRLCK), Start: 20, Len: 5}
+	if errc := second.Lock("/file", fuse.F_GETLK, &lock, secondHandle, 1); errc != 0 || lock.Type != int16(fuse.F_UNLCK) {
+		t.Errorf("F_GETLK of a free range = %d, %+v, want F_UNLCK", errc, lock)
+	}
+
+	// F_SETLKW waits until the first client unlocks the range.
+	locked := make(chan int)
+	go func() {
+		locked <- second.Lock("/file", fuse.F_SETLKW, &fuse.Lock_t{Type: int16(fuse.F_WRLCK), Start: 0, Len: 0}, secondHandle, 2)
+	}()
+
+	select {
+	case errc := <-locked:
+		t.Fatalf("F_SETLKW of a conflicting lock returned %d without waiting", errc)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if errc := first.Lock("/file", fuse.F_SETLK, &fuse.Lock_t{Type: int16(fuse.F_UNLCK), Start: 10, Len: 10}, firstHandle, 1); errc != 0 {
+		t.Fatalf("F_SETLK F_UNLCK = %d", errc)
+	}
+
+	select {
+	case errc := <-locked:
+		if errc != 0 {
+			t.Errorf("F_SETLKW = %d", errc)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("F_SETLKW didn't return after the range was unlocked")
+	}
+
+	if errc := first.Lock("/file", fuse.F_SETLK, &fuse.Lock_t{Type: int16(fuse.F_WRLCK), Whence: 1}, firstHandle, 1); errc != -fuse.EINVAL {
+		t.Errorf("F_SETLK relative to the current offset = %d, want EINVAL", errc)
+	}
+}
+
+func TestFlock(t *testing.T) {
+	srv := startServer(t)
+	defer srv.stop()
+
+	first, firstHandle := mount(t, srv, "/file")
+	second, secondHandle := mount(t, srv, "/file")
+
+	if errc := first.Flock("/file", fuse.LOCK_SH, firstHandle, 1); errc != 0 {
+		t.Fatalf("LOCK_SH = %d", errc)
+	}
+
+	if errc := second.Flock("/file", fuse.LOCK_SH|fuse.LOCK_NB, secondHandle, 2); errc != 0 {
+		t.Errorf("LOCK_SH of a shared file = %d", errc)
+	}
+
+	if errc := second.Flock("/file", fuse.LOCK_EX|fuse.LOCK_NB, secondHandle, 2); errc != -fuse.EAGAIN {
+		t.Errorf("LOCK_EX of a shared file = %d, want EAGAIN", errc)
+	}
+
+	// Whole file locks don't conflict with byte range locks.
+	if errc := second.Lock("/file", fuse.F_SETLK, &fuse.Lock_t{Type: int16(fuse.F_WRLCK)}, secondHandle, 2); errc != 0 {
+		t.Errorf("F_SETLK of a file with a whole file lock = %d", errc)
+	}
+
+	if errc := first.Flock("/file", fuse.LOCK_UN, firstHandle, 1); errc != 0 {
+		t.Fatalf("LOCK_UN = %d", errc)
+	}
+
+	if errc := second.Flock("/file", fuse.LOCK_EX|fuse.LOCK_NB, secondHandle, 2); errc != 0 {
+		t.Errorf("LOCK_EX after the other lock was removed = %d", errc)
+	}
+
+	// Closing a file releases its whole file lock.
+	second.Release("/file", secondHandle)
+	if errc := first.Flock("/file", fuse.LOCK_EX|fuse.LOCK_NB, firstHandle, 1); errc != 0 {
+		t.Errorf("LOCK_EX after the locked file was closed = %d", errc)
+	}
+}
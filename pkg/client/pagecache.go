@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// PageCache buffers the data of open files, so small reads and writes don't
+// need a round trip to the server each:
+//
+//   - Sequential reads fetch readAhead bytes at once, and the following reads
+//     are served from memory.
+//   - Writes that continue each other are collected into a single range of
+//     up to writeBehind bytes, which is written when it's full, and when the
+//     file is flushed, synced or closed.
+//
+// Consistency is tied to the open file handle. A handle always reads its own
+// writes. Within this client, reading a file first writes the pending data of
+// every handle of the file, and writing a file drops the read-ahead data of
+// every handle of the file. Other clients see the writes once the file is
+// flushed or closed, and changes that the server notifies about drop the
+// read-ahead data of the file.
+//
+// If both readAhead and writeBehind are 0, files aren't buffered at all.
+type PageCache struct {
+	client      *FileboxClient
+	readAhead   int
+	writeBehind int
+
+	mutex sync.Mutex // also guards the paths of the files
+	files map[uint64]*fileBuffer
+}
+
+// fileBuffer is the buffered data of a single open file handle.
+type fileBuffer struct {
+	path string // guarded by PageCache.mutex
+
+	mutex sync.Mutex
+
+	// Data that was read ahead, starting at readOffset.
+	readOffset int64
+	readData   []byte
+	readEnd    int64 // where the last read ended, to detect sequential reads
+
+	// Data that wasn't written yet, starting at writeOffset.
+	writeOffset int64
+	writeData   []byte
+}
+
+// NewPageCache creates a page cache that reads ahead readAhead bytes, and
+// collects up to writeBehind bytes of writes.
+func NewPageCache(client *FileboxClient, readAhead int, writeBehind int) *PageCache {
+	return &PageCache{
+		client:      client,
+		readAhead:   readAhead,
+		writeBehind: writeBehind,
+		files:       make(map[uint64]*fileBuffer),
+	}
+}
+
+// Open starts buffering an open file.
+func (cache *PageCache) Open(fh uint64, path string) {
+	if cache.readAhead <= 0 && cache.writeBehind <= 0 {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.files[fh] = &fileBuffer{path: path}
+}
+
+// filesOf returns the buffers of all the open handles of a file.
+func (cache *PageCache) filesOf(path string) []*fileBuffer {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	var files []*fileBuffer
+	for _, file := range cache.files {
+		if file.path == path {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// Read reads from an open file, through the read-ahead buffer of its handle.
+func (cache *PageCache) Read(ctx context.Context, fh uint64, buff []byte, offset int64) (int, error) {
+	file := cache.file(fh)
+	if file == nil {
+		return cache.client.ReadAt(ctx, fh, buff, offset)
+	}
+
+	if err := cache.FlushPath(ctx, cache.pathOf(file)); err != nil {
+		return 0, err
+	}
+
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	sequential := offset == file.readEnd
+	file.readEnd = offset + int64(len(buff))
+
+	if offset >= file.readOffset && offset+int64(len(buff)) <= file.readOffset+int64(len(file.readData)) {
+		return copy(buff, file.readData[offset-file.readOffset:]), nil
+	}
+
+	if !sequential || len(buff) >= cache.readAhead {
+		file.readData = nil
+		return cache.client.ReadAt(ctx, fh, buff, offset)
+	}
+
+	data := make([]byte, cache.readAhead)
+	bytesRead, err := cache.client.ReadAt(ctx, fh, data, offset)
+	if err != nil {
+		file.readData = nil
+		return 0, err
+	}
+
+	file.readOffset = offset
+	file.readData = data[:bytesRead]
+
+	if bytesRead < len(buff) {
+		return copy(buff, file.readData), nil
+	}
+
+	return copy(buff, file.readData[:len(buff)]), nil
+}
+
+// Write writes to an open file, through the write-behind buffer of its
+// handle. Errors of buffered writes are returned by later calls, such as
+// Flush.
+func (cache *PageCache) Write(ctx context.Context, fh uint64, data []byte, offset int64) (int, error) {
+	file := cache.file(fh)
+	if file == nil {
+		return cache.client.WriteAt(ctx, fh, data, offset)
+	}
+
+	cache.InvalidatePath(cache.pathOf(file))
+
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	if len(file.writeData) > 0 {
+		continues := offset == file.writeOffset+int64(len(file.writeData))
+		if continues && len(file.writeData)+len(data) <= cache.writeBehind {
+			file.writeData = append(file.writeData, data...)
+			return len(data), nil
+		}
+
+		if err := cache.flush(ctx, fh, file); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(data) >= cache.writeBehind {
+		return cache.client.WriteAt(ctx, fh, data, offset)
+	}
+
+	file.writeOffset = offset
+	file.writeData = append(make([]byte, 0, cache.writeBehind), data...)
+	return len(data), nil
+}
+
+// Flush writes the pending data of an open file.
+func (cache *PageCache) Flush(ctx context.Context, fh uint64) error {
+	file := cache.file(fh)
+	if file == nil {
+		return nil
+	}
+
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	return cache.flush(ctx, fh, file)
+}
+
+// FlushPath writes the pending data of every open handle of a file. It's
+// called before requests that depend on the data, such as GetFileAttributes.
+func (cache *PageCache) FlushPath(ctx context.Context, path string) error {
+	cache.mutex.Lock()
+	var handles []uint64
+	for fh, file := range cache.files {
+		if file.path == path {
+			handles = append(handles, fh)
+		}
+	}
+	cache.mutex.Unlock()
+
+	for _, fh := range handles {
+		if err := cache.Flush(ctx, fh); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release writes the pending data of an open file, and stops buffering it.
+func (cache *PageCache) Release(ctx context.Context, fh uint64) error {
+	err := cache.Flush(ctx, fh)
+
+	cache.mutex.Lock()
+	delete(cache.files, fh)
+	cache.mutex.Unlock()
+
+	return err
+}
+
+// InvalidatePath drops the read-ahead data of every open handle of a file.
+func (cache *PageCache) InvalidatePath(path string) {
+	for _, file := range cache.filesOf(path) {
+		file.mutex.Lock()
+		file.readData = nil
+		file.mutex.Unlock()
+	}
+}
+
+// Rename updates the paths of the open files after a successful rename.
+func (cache *PageCache) Rename(oldPath string, newPath string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for _, file := range cache.files {
+		if file.path == oldPath {
+			file.path = newPath
+		} else if strings.HasPrefix(file.path, oldPath+"/") {
+			file.path = newPath + strings.TrimPrefix(file.path, oldPath)
+		}
+	}
+}
+
+// file returns the buffer of an open file handle, or nil if it isn't buffered.
+func (cache *PageCache) file(fh uint64) *fileBuffer {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.files[fh]
+}
+
+func (cache *PageCache) pathOf(file *fileBuffer) string {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return file.path
+}
+
+// flush writes the pending data of a file. file.mutex must be locked.
+func (cache *PageCache) flush(ctx context.Context, fh uint64, file *fileBuffer) error {
+	if len(file.writeData) == 0 {
+		return nil
+	}
+
+	_, err := cache.client.WriteAt(ctx, fh, file.writeData, file.writeOffset)
+	file.writeData = file.writeData[:0]
+	return err
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// transferWindow is the number of chunks of a single read or write that can
+// wait for a response at the same time. Together with the server's limit on
+// requests in flight, it keeps large transfers from flooding the connection.
+const transferWindow = 4
+
+// ReadAt reads len(buff) bytes from an open file, starting at offset. Ranges
+// that are larger than protocol.MaxChunkSize are read in several requests.
+// It returns the number of bytes that were read, which is smaller than
+// len(buff) at the end of the file.
+func (client *FileboxClient) ReadAt(ctx context.Context, fileHandle uint64, buff []byte, offset int64) (int, error) {
+	bytesRead := make([]int, chunkCount(len(buff)))
+
+	err := forEachChunk(ctx, len(buff), func(ctx context.Context, chunk int, start int, end int) error {
+		response, err := client.SendReceive(ctx, protocol.ReadFileRequest{
+			FileHandle: fileHandle,
+			Offset:     offset + int64(start),
+			Size:       end - start,
+		})
+		if err != nil {
+			return err
+		}
+
+		copy(buff[start:end], response.(protocol.ReadFileResponse).Data)
+		bytesRead[chunk] = response.(protocol.ReadFileResponse).BytesRead
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The data is contiguous up to the first chunk that was cut short.
+	total := 0
+	for chunk, n := range bytesRead {
+		total += n
+		if n < chunkSize(len(buff), chunk) {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// WriteAt writes data to an open file, starting at offset. Data that is larger
+// than protocol.MaxChunkSize is written in several requests.
+func (client *FileboxClient) WriteAt(ctx context.Context, fileHandle uint64, data []byte, offset int64) (int, error) {
+	bytesWritten := make([]int, chunkCount(len(data)))
+
+	err := forEachChunk(ctx, len(data), func(ctx context.Context, chunk int, start int, end int) error {
+		response, err := client.SendReceive(ctx, protocol.WriteFileRequest{
+			FileHandle: fileHandle,
+			Offset:     offset + int64(start),
+			Data:       data[start:end],
+		})
+		if err != nil {
+			return err
+		}
+
+		bytesWritten[chunk] = response.(protocol.WriteFileResponse).BytesWritten
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, n := range bytesWritten {
+		total += n
+	}
+
+	return total, nil
+}
+
+func chunkCount(size int) int {
+	if size == 0 {
+		return 1
+	}
+
+	return (size + protocol.MaxChunkSize - 1) / protocol.MaxChunkSize
+}
+
+func chunkSize(size int, chunk int) int {
+	if end := (chunk + 1) * protocol.MaxChunkSize; end < size {
+		return protocol.MaxChunkSize
+	}
+
+	return size - chunk*protocol.MaxChunkSize
+}
+
+// forEachChunk splits a range of size bytes into chunks of at most
+// protocol.MaxChunkSize, and transfers up to transferWindow of them at once.
+// If a chunk fails, the chunks that haven't started are skipped, and its
+// error is returned.
+func forEachChunk(ctx context.Context, size int, transfer func(ctx context.Context, chunk int, start int, end int) error) error {
+	if size <= protocol.MaxChunkSize {
+		return transfer(ctx, 0, 0, size)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		window   = make(chan struct{}, transferWindow)
+		pending  sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for chunk := 0; chunk < chunkCount(size); chunk++ {
+		select {
+		case window <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		start := chunk * protocol.MaxChunkSize
+		end := start + chunkSize(size, chunk)
+
+		pending.Add(1)
+		go func(chunk int) {
+			defer pending.Done()
+			defer func() { <-window }()
+
+			if err := transfer(ctx, chunk, start, end); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(chunk)
+	}
+
+	pending.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return contextError(ctx)
+}
@@ -0,0 +1,240 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Encoding is a wire format of the protocol. The messages are the same in
+// every encoding.
+type Encoding string
+
+const (
+	// EncodingGob encodes messages with encoding/gob. It's the default, and
+	// it's only practical for clients that are written in Go.
+	EncodingGob Encoding = "gob"
+
+	// EncodingProtobuf encodes messages as length-prefixed protobuf frames,
+	// as described in pb/filebox.proto.
+	EncodingProtobuf Encoding = "protobuf"
+)
+
+// ProtobufPreface is sent by clients at the beginning of a connection to
+// select the protobuf encoding. A gob stream never starts with a zero byte,
+// so the server can tell the encodings apart.
+var ProtobufPreface = []byte{0, 'F', 'B', 'P'}
+
+const (
+	// MaxMessageSize is the largest message that is sent or accepted, in any
+	// encoding. It keeps a peer from making the other side allocate too much
+	// memory.
+	MaxMessageSize = 16 << 20
+
+	// MaxChunkSize is the largest amount of file data in a single ReadFile or
+	// WriteFile request. Larger ranges must be split into several requests.
+	MaxChunkSize = 1 << 20
+
+	// MaxDirectoryPage is the largest number of files in a page of a
+	// ReadDirectory response. A page of files with the longest names is
+	// still much smaller than MaxMessageSize.
+	MaxDirectoryPage = 1024
+)
+
+var (
+	// ErrUnknownEncoding is returned for encodings that this package doesn't support.
+	ErrUnknownEncoding = errors.New("unknown encoding")
+
+	// ErrMessageTooLarge is returned for messages that are larger than MaxMessageSize.
+	ErrMessageTooLarge = errors.New("message is too large")
+)
+
+// Encoder writes messages to a connection.
+type Encoder interface {
+	Encode(message *Message) error
+}
+
+// Decoder reads messages from a connection.
+type Decoder interface {
+	Decode(message *Message) error
+}
+
+type gobEncoder struct {
+	encoder *gob.Encoder
+}
+
+// Encode fails with ErrMessageTooLarge, without writing anything, if the
+// message is larger than MaxMessageSize, so the connection can still be used
+// afterwards. gob writes a message in several parts, and remembers which
+// types it sent, so a message can't be dropped after it's encoded. Messages
+// that might be too large are measured with another encoder first.
+func (encoder gobEncoder) Encode(message *Message) error {
+	if gobSizeBound(reflect.ValueOf(message)) > MaxMessageSize {
+		var size countingWriter
+		if err := gob.NewEncoder(&size).Encode(message); err != nil {
+			return err
+		}
+
+		if size > MaxMessageSize {
+			return fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, size)
+		}
+	}
+
+	return encoder.encoder.Encode(message)
+}
+
+// gobSizeBound returns an upper bound of the size of a value in gob, which is
+// much cheaper to compute than encoding it.
+func gobSizeBound(value reflect.Value) int {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return 1
+		}
+
+		// Interface values carry the name of their type, and the
+		// definitions of the types in it when they're first sent.
+		return 4096 + gobSizeBound(value.Elem())
+
+	case reflect.String:
+		return 10 + value.Len()
+
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return 10 + value.Len()
+		}
+
+		size := 10
+		for i := 0; i < value.Len(); i++ {
+			size += gobSizeBound(value.Index(i))
+		}
+		return size
+
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return 32
+		}
+
+		size := 10
+		for i := 0; i < value.NumField(); i++ {
+			size += 10 + gobSizeBound(value.Field(i))
+		}
+		return size
+	}
+
+	// Numbers and booleans.
+	return 10
+}
+
+// countingWriter counts the bytes that are written to it, and discards them.
+type countingWriter int
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	*writer += countingWriter(len(p))
+	return len(p), nil
+}
+
+type gobDecoder struct {
+	decoder *gob.Decoder
+	limit   *messageLimitReader
+}
+
+func (decoder gobDecoder) Decode(message *Message) error {
+	decoder.limit.remaining = MaxMessageSize + readAheadSize
+	return decoder.decoder.Decode(message)
+}
+
+// readAheadSize is the size of the buffer that gob reads into. The limit
+// of gob messages is only accurate up to it.
+const readAheadSize = 4096
+
+// messageLimitReader fails when a single message is larger than the bytes
+// that remain. gob reads the body of a message gradually, so it never
+// allocates much more than the bytes that were actually sent.
+type messageLimitReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (reader *messageLimitReader) Read(p []byte) (int, error) {
+	if reader.remaining <= 0 {
+		return 0, ErrMessageTooLarge
+	}
+
+	if int64(len(p)) > reader.remaining {
+		p = p[:reader.remaining]
+	}
+
+	n, err := reader.reader.Read(p)
+	reader.remaining -= int64(n)
+	return n, err
+}
+
+// NewEncoder returns an encoder that writes messages in the given encoding.
+func NewEncoder(encoding Encoding, writer io.Writer) (Encoder, error) {
+	switch encoding {
+	case EncodingGob, "":
+		return gobEncoder{gob.NewEncoder(writer)}, nil
+	case EncodingProtobuf:
+		return &protobufEncoder{writer: writer}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEncoding, encoding)
+}
+
+// NewDecoder returns a decoder that reads messages in the given encoding.
+func NewDecoder(encoding Encoding, reader io.Reader) (Decoder, error) {
+	switch encoding {
+	case EncodingGob, "":
+		limit := &messageLimitReader{reader: reader}
+		return gobDecoder{gob.NewDecoder(bufio.NewReaderSize(limit, readAheadSize)), limit}, nil
+	case EncodingProtobuf:
+		if _, ok := reader.(io.ByteReader); !ok {
+			reader = bufio.NewReader(reader)
+		}
+		return &protobufDecoder{reader: reader}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEncoding, encoding)
+}
+
+// WritePreface selects an encoding at the beginning of a client connection.
+func WritePreface(writer io.Writer, encoding Encoding) error {
+	switch encoding {
+	case EncodingGob, "":
+		return nil
+	case EncodingProtobuf:
+		_, err := writer.Write(ProtobufPreface)
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownEncoding, encoding)
+}
+
+// ReadPreface returns the encoding that a client selected at the beginning of
+// its connection. The rest of the connection must be read from reader.
+func ReadPreface(reader *bufio.Reader) (Encoding, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return "", err
+	}
+
+	if first[0] != ProtobufPreface[0] {
+		return EncodingGob, nil
+	}
+
+	preface := make([]byte, len(ProtobufPreface))
+	if _, err := io.ReadFull(reader, preface); err != nil {
+		return "", err
+	}
+
+	if !bytes.Equal(preface, ProtobufPreface) {
+		return "", fmt.Errorf("%w: preface %x", ErrUnknownEncoding, preface)
+	}
+
+	return EncodingProtobuf, nil
+}
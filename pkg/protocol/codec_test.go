@@ -0,0 +1,195 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeTooLarge(t *testing.T) {
+	Init()
+
+	for _, encoding := range []Encoding{EncodingGob, EncodingProtobuf} {
+		t.Run(string(encoding), func(t *testing.T) {
+			var stream bytes.Buffer
+			encoder, err := NewEncoder(encoding, &stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The first message of its type, so gob also sends its type
+			// definitions, which the next message needs.
+			large := &Message{MessageID: 1, IsResponse: true, Data: ReadFileResponse{Data: make([]byte, MaxMessageSize+1)}}
+			if err := encoder.Encode(large); !errors.Is(err, ErrMessageTooLarge) {
+				t.Fatalf("Encode() of a large message = %v, want ErrMessageTooLarge", err)
+			}
+
+			small := &Message{MessageID: 2, IsResponse: true, Data: ReadFileResponse{Data: []byte("data"), BytesRead: 4}}
+			if err := encoder.Encode(small); err != nil {
+				t.Fatal(err)
+			}
+
+			decoder, err := NewDecoder(encoding, &stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var message Message
+			if err := decoder.Decode(&message); err != nil {
+				t.Fatal(err)
+			}
+
+			response, ok := message.Data.(ReadFileResponse)
+			if message.MessageID != 2 || !ok || string(response.Data) != "data" {
+				t.Errorf("Decode() = %+v, want the message after the large one", message)
+			}
+		})
+	}
+}
+
+func TestGobSizeBound(t *testing.T) {
+	Init()
+
+	files := make([]FileInfo, 1000)
+	for i := range files {
+		files[i] = FileInfo{Name: fmt.Sprintf("file-%d", i), Size: int64(i) << 40, ModTime: time.Now(), Nlink: 1}
+	}
+
+	messages := []*Message{
+		{MessageID: 1, IsResponse: true, Data: ReadDirectoryResponse{Files: files, More: true}},
+		{MessageID: 2, IsResponse: true, Data: ReadFileResponse{Data: make([]byte, MaxChunkSize), BytesRead: MaxChunkSize}},
+		{MessageID: 3, Data: HelloRequest{Version: Version, Capabilities: Capabilities{CapabilityNotifications, CapabilityLocks}}},
+		{MessageID: 4, IsResponse: true, Data: &EmptyResponse{}, ErrorCode: ErrorNotFound, ErrorMessage: "not found"},
+	}
+
+	for _, message := range messages {
+		// A new encoder sends the definitions of all the types too.
+		var size countingWriter
+		if err := gob.NewEncoder(&size).Encode(message); err != nil {
+			t.Fatal(err)
+		}
+
+		if bound := gobSizeBound(reflect.ValueOf(message)); bound < int(size) {
+			t.Errorf("gobSizeBound(%T) = %d, but it's encoded in %d bytes", message.Data, bound, size)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	Init()
+
+	// Times that protobuf keeps exactly, since it sends Unix nanoseconds.
+	modTime := time.Unix(1500000000, 123456789)
+	accessTime := time.Unix(1600000000, 1)
+	file := FileInfo{
+		Name:       "file",
+		Size:       1 << 40,
+		Mode:       os.ModeDir | os.ModeSetuid | 0755,
+		ModTime:    modTime,
+		IsDir:      true,
+		Uid:        1000,
+		Gid:        1001,
+		AccessTime: accessTime,
+		ChangeTime: modTime,
+		Nlink:      2,
+	}
+
+	messages := []interface{}{
+		EmptyResponse{},
+		HelloRequest{Version: Version, MinVersion: MinVersion, Capabilities: Capabilities{CapabilityNotifications, CapabilityLocks}},
+		HelloResponse{Version: Version, Capabilities: Capabilities{CapabilitySymlinks}},
+		AuthenticateRequest{Username: "user", Token: "token"},
+		AuthenticateResponse{Username: "user"},
+		OpenFileRequest{Path: "/file", Flags: os.O_RDWR | os.O_CREATE},
+		OpenFileResponse{FileHandle: 1 << 63},
+		ReadFileRequest{FileHandle: 1, Offset: 1 << 33, Size: 4096},
+		ReadFileResponse{Data: []byte("data"), BytesRead: 4},
+		ReadDirectoryRequest{Path: "/", After: "a", Limit: MaxDirectoryPage},
+		ReadDirectoryResponse{Files: []FileInfo{file, {Name: "empty"}}, More: true},
+		GetFileAttributesRequest{Path: "/file", FileHandle: 2},
+		GetFileAttributesResponse{FileInfo: file},
+		CloseFileRequest{FileHandle: 3},
+		CreateDirectoryRequest{Path: "/directory", Mode: 0700},
+		CreateFileRequest{Path: "/file"},
+		RenameRequest{OldPath: "/old", NewPath: "/new"},
+		DeleteDirectoryRequest{Path: "/directory"},
+		TruncateRequest{Path: "/file", FileHandle: 4, Size: 100},
+		DeleteFileRequest{Path: "/file"},
+		WriteFileRequest{FileHandle: 5, Offset: -1, Data: []byte{0, 1, 2}},
+		WriteFileResponse{BytesWritten: 3},
+		ChangeNotification{Type: ChangeRenamed, Path: "/old", NewPath: "/new"},
+		FsyncFileRequest{FileHandle: 6},
+		FsyncDirectoryRequest{Path: "/directory"},
+		CreateSymlinkRequest{Path: "/link", Target: "../target"},
+		ReadSymlinkRequest{Path: "/link"},
+		ReadSymlinkResponse{Target: "../target"},
+		CreateHardLinkRequest{OldPath: "/file", NewPath: "/link"},
+		GetExtendedAttributeRequest{Path: "/file", Name: "user.name"},
+		GetExtendedAttributeResponse{Value: []byte("value")},
+		ListExtendedAttributesRequest{Path: "/file"},
+		ListExtendedAttributesResponse{Names: []string{"user.a", "user.b"}},
+		SetExtendedAttributeRequest{Path: "/file", Name: "user.name", Value: []byte("value"), Flags: XattrReplace},
+		RemoveExtendedAttributeRequest{Path: "/file", Name: "user.name"},
+		LockRequest{FileHandle: 7, Kind: LockRange, Type: LockExclusive, Start: 10, Length: 20, Owner: 1234},
+		UnlockRequest{FileHandle: 7, Kind: LockFile, Start: 10, Length: 20, Owner: 1234},
+		TestLockRequest{FileHandle: 7, Kind: LockRange, Type: LockShared, Start: 10, Length: 20, Owner: 1234},
+		TestLockResponse{Type: LockExclusive, Start: 10, Length: 20},
+		ChangeModeRequest{Path: "/file", Mode: 04755},
+		ChangeOwnerRequest{Path: "/file", Uid: -1, Gid: 1000},
+		ChangeTimesRequest{Path: "/file", AccessTime: accessTime, ModTime: modTime},
+		StatfsRequest{Path: "/"},
+		StatfsResponse{BlockSize: 4096, Blocks: 1000, BlocksFree: 500, BlocksAvailable: 400, Files: 100, FilesFree: 50, NameLength: 255},
+		QuotaUsageRequest{},
+		QuotaUsageResponse{Quotas: []QuotaUsage{{User: "user", Limit: 1 << 30, Usage: 100}, {Path: "/shared", Limit: 1 << 20}}},
+	}
+
+	tested := make(map[reflect.Type]bool)
+	for _, data := range messages {
+		tested[reflect.TypeOf(data)] = true
+	}
+	for _, protobufType := range protobufTypes {
+		if native := reflect.TypeOf(protobufType.native); !tested[native] {
+			t.Errorf("%s isn't tested", native.Name())
+		}
+	}
+
+	for _, encoding := range []Encoding{EncodingGob, EncodingProtobuf} {
+		var stream bytes.Buffer
+		encoder, err := NewEncoder(encoding, &stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder, err := NewDecoder(encoding, &stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, data := range messages {
+			sent := Message{MessageID: uint32(i + 1), IsResponse: i%2 == 0, Data: data}
+			if i%5 == 0 {
+				sent.ErrorCode = ErrorNotFound
+				sent.ErrorMessage = "not found"
+			}
+
+			if err := encoder.Encode(&sent); err != nil {
+				t.Errorf("%s: Encode(%T) failed: %v", encoding, data, err)
+				continue
+			}
+
+			var received Message
+			if err := decoder.Decode(&received); err != nil {
+				t.Fatalf("%s: Decode(%T) failed: %v", encoding, data, err)
+			}
+
+			if !reflect.DeepEqual(received, sent) {
+				t.Errorf("%s: %T changed in a round trip:\nsent     %+v\nreceived %+v", encoding, data, sent, received)
+			}
+		}
+	}
+}
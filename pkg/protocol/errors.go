@@ -0,0 +1,108 @@
+package protocol
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// ErrorCode describes why a request failed. The codes don't depend on the
+// operating system, because the client and the server can run on different
+// ones.
+type ErrorCode uint32
+
+const (
+	ErrorNone ErrorCode = iota
+	ErrorUnknown
+	ErrorNotFound
+	ErrorPermission
+	ErrorExists
+	ErrorNotEmpty
+	ErrorIsDirectory
+	ErrorNotDirectory
+	ErrorInvalid
+	ErrorBadFileHandle
+	ErrorNoSpace
+	ErrorNameTooLong
+	ErrorBusy
+	ErrorCrossDevice
+	ErrorLoop
+	ErrorNotSupported
+	ErrorReadOnly
+	ErrorVersionMismatch
+	ErrorNoAttribute
+	ErrorLocked
+	ErrorQuotaExceeded
+)
+
+// Error is a failed request, as reported by the server.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+type errnoCode struct {
+	errno syscall.Errno
+	code  ErrorCode
+}
+
+// errnoCodes maps system errors to error codes. It's checked in order. The
+// errors of missing extended attributes differ between systems, so they're
+// added in errors_linux.go and errors_bsd.go.
+var errnoCodes = []errnoCode{
+	{syscall.ENOENT, ErrorNotFound},
+	{syscall.EACCES, ErrorPermission},
+	{syscall.EPERM, ErrorPermission},
+	{syscall.EEXIST, ErrorExists},
+	{syscall.ENOTEMPTY, ErrorNotEmpty},
+	{syscall.EISDIR, ErrorIsDirectory},
+	{syscall.ENOTDIR, ErrorNotDirectory},
+	{syscall.EINVAL, ErrorInvalid},
+	{syscall.EBADF, ErrorBadFileHandle},
+	{syscall.ENOSPC, ErrorNoSpace},
+	{syscall.ENAMETOOLONG, ErrorNameTooLong},
+	{syscall.EBUSY, ErrorBusy},
+	{syscall.EXDEV, ErrorCrossDevice},
+	{syscall.ELOOP, ErrorLoop},
+	{syscall.ENOTSUP, ErrorNotSupported},
+	{syscall.EOPNOTSUPP, ErrorNotSupported},
+	{syscall.EROFS, ErrorReadOnly},
+	{syscall.EAGAIN, ErrorLocked},
+	{syscall.EDQUOT, ErrorQuotaExceeded},
+}
+
+// ErrorCodeOf returns the error code that describes err.
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ErrorNone
+	}
+
+	var protocolError *Error
+	if errors.As(err, &protocolError) {
+		return protocolError.Code
+	}
+
+	for _, entry := range errnoCodes {
+		if errors.Is(err, entry.errno) {
+			return entry.code
+		}
+	}
+
+	// Fall back to the portable checks, which also understand Windows errors.
+	switch {
+	case os.IsNotExist(err):
+		return ErrorNotFound
+	case os.IsPermission(err), errors.Is(err, os.ErrPermission):
+		return ErrorPermission
+	case os.IsExist(err):
+		return ErrorExists
+	case errors.Is(err, os.ErrInvalid):
+		return ErrorInvalid
+	}
+
+	return ErrorUnknown
+}
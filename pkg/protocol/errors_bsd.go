@@ -0,0 +1,10 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package protocol
+
+import "syscall"
+
+func init() {
+	errnoCodes = append(errnoCodes, errnoCode{syscall.ENOATTR, ErrorNoAttribute})
+}
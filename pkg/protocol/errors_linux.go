@@ -0,0 +1,7 @@
+package protocol
+
+import "syscall"
+
+func init() {
+	errnoCodes = append(errnoCodes, errnoCode{syscall.ENODATA, ErrorNoAttribute})
+}
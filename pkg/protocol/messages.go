@@ -7,20 +7,37 @@ import (
 )
 
 type Message struct {
-	MessageID  uint32
-	IsResponse bool
-	Data       interface{}
-	Success    bool
+	MessageID    uint32
+	IsResponse   bool
+	Data         interface{}
+	ErrorCode    ErrorCode // ErrorNone if the request succeeded
+	ErrorMessage string
 }
 
 type EmptyResponse struct{}
 
 type FileInfo struct {
-	Name    string      // base name of the file
-	Size    int64       // length in bytes for regular files; system-dependent for others
-	Mode    os.FileMode // file mode bits
-	ModTime time.Time   // modification time
-	IsDir   bool        // abbreviation for Mode().IsDir()
+	Name       string      // base name of the file
+	Size       int64       // length in bytes for regular files; system-dependent for others
+	Mode       os.FileMode // file mode bits
+	ModTime    time.Time   // modification time
+	IsDir      bool        // abbreviation for Mode().IsDir()
+	Uid        uint32      // user ID of the owner on the server; 0 on Windows
+	Gid        uint32      // group ID of the owner on the server; 0 on Windows
+	AccessTime time.Time   // last access time
+	ChangeTime time.Time   // last status change time; creation time on Windows
+	Nlink      uint32      // number of hard links
+}
+
+// AuthenticateRequest must be sent right after the hello exchange, before any
+// other request. The server closes the connection if the credentials are wrong.
+type AuthenticateRequest struct {
+	Username string
+	Token    string
+}
+
+type AuthenticateResponse struct {
+	Username string
 }
 
 type OpenFileRequest struct {
@@ -43,12 +60,18 @@ type ReadFileResponse struct {
 	BytesRead int
 }
 
+// ReadDirectoryRequest lists a directory in name order. Large directories are
+// read in pages: each request continues after the last name of the previous
+// page, until a response has More set to false.
 type ReadDirectoryRequest struct {
-	Path string
+	Path  string
+	After string // only list the files whose names sort after it
+	Limit int    // the most files in the response, up to MaxDirectoryPage; 0 lists all of them
 }
 
 type ReadDirectoryResponse struct {
 	Files []FileInfo
+	More  bool // the directory has files after the last one in Files
 }
 
 type GetFileAttributesRequest struct {
@@ -102,8 +125,213 @@ type WriteFileResponse struct {
 	BytesWritten int
 }
 
+// CreateSymlinkRequest creates a symbolic link at Path that points to Target.
+// Target must be relative, and must not point outside of the shared directory.
+type CreateSymlinkRequest struct {
+	Path   string
+	Target string
+}
+
+type ReadSymlinkRequest struct {
+	Path string
+}
+
+type ReadSymlinkResponse struct {
+	Target string
+}
+
+// CreateHardLinkRequest creates NewPath as another name for the file at OldPath.
+type CreateHardLinkRequest struct {
+	OldPath string
+	NewPath string
+}
+
+// LockKind says which locking API a lock comes from. Locks of different kinds
+// don't conflict with each other, like on Linux.
+type LockKind int
+
+const (
+	LockRange LockKind = iota + 1 // a byte range lock of fcntl(2), owned by a process
+	LockFile                      // a whole file lock of flock(2), owned by an open file
+)
+
+type LockType int
+
+const (
+	LockNone      LockType = iota // no lock, in TestLockResponse
+	LockShared                    // a read lock, which other shared locks don't conflict with
+	LockExclusive                 // a write lock, which conflicts with any other lock
+)
+
+// LockRequest locks a range of an open file. If a lock of another owner
+// conflicts with it, the request fails with ErrorLocked instead of waiting.
+// The locks of a connection are released when it closes the file, and when
+// it disconnects.
+type LockRequest struct {
+	FileHandle uint64
+	Kind       LockKind
+	Type       LockType
+	Start      int64
+	Length     int64  // 0 locks until the end of the file, however long it grows
+	Owner      uint64 // e.g the process ID; only for LockRange
+}
+
+type UnlockRequest struct {
+	FileHandle uint64
+	Kind       LockKind
+	Start      int64
+	Length     int64
+	Owner      uint64
+}
+
+// TestLockRequest checks whether a lock could be placed, like F_GETLK.
+type TestLockRequest struct {
+	FileHandle uint64
+	Kind       LockKind
+	Type       LockType
+	Start      int64
+	Length     int64
+	Owner      uint64
+}
+
+// TestLockResponse describes a lock that conflicts with the tested one. Its
+// Type is LockNone if there's no such lock.
+type TestLockResponse struct {
+	Type   LockType
+	Start  int64
+	Length int64
+}
+
+// Flags of SetExtendedAttributeRequest. They have the same values as the
+// XATTR_CREATE and XATTR_REPLACE flags of setxattr(2).
+const (
+	XattrCreate  = 1 // fail if the attribute already exists
+	XattrReplace = 2 // fail if the attribute doesn't exist
+)
+
+// GetExtendedAttributeRequest reads an extended attribute of a file. It fails
+// with ErrorNoAttribute if the file doesn't have the attribute.
+type GetExtendedAttributeRequest struct {
+	Path string
+	Name string
+}
+
+type GetExtendedAttributeResponse struct {
+	Value []byte
+}
+
+type ListExtendedAttributesRequest struct {
+	Path string
+}
+
+type ListExtendedAttributesResponse struct {
+	Names []string
+}
+
+type SetExtendedAttributeRequest struct {
+	Path  string
+	Name  string
+	Value []byte
+	Flags int // XattrCreate, XattrReplace or 0
+}
+
+type RemoveExtendedAttributeRequest struct {
+	Path string
+	Name string
+}
+
+// ChangeModeRequest changes the permission bits of a file, like chmod(2).
+type ChangeModeRequest struct {
+	Path string
+	Mode uint32 // Unix permission bits, including the setuid, setgid and sticky bits
+}
+
+// ChangeOwnerRequest changes the owner and the group of a file, like chown(2).
+type ChangeOwnerRequest struct {
+	Path string
+	Uid  int // -1 keeps the current owner
+	Gid  int // -1 keeps the current group
+}
+
+// ChangeTimesRequest changes the access and modification times of a file.
+type ChangeTimesRequest struct {
+	Path       string
+	AccessTime time.Time // the zero time keeps the current access time
+	ModTime    time.Time // the zero time keeps the current modification time
+}
+
+// FsyncFileRequest writes the data of an open file to the server's disk.
+type FsyncFileRequest struct {
+	FileHandle uint64
+}
+
+// FsyncDirectoryRequest writes the entries of a directory to the server's
+// disk, so files that were created, renamed or deleted in it survive a crash.
+type FsyncDirectoryRequest struct {
+	Path string
+}
+
+// StatfsRequest returns the size and the free space of the file system that
+// contains a path on the server.
+type StatfsRequest struct {
+	Path string
+}
+
+// StatfsResponse is the size of a file system, like struct statvfs. Block
+// counts are in units of BlockSize. Files and FilesFree are 0 if the file
+// system doesn't limit the number of files.
+type StatfsResponse struct {
+	BlockSize       uint64
+	Blocks          uint64
+	BlocksFree      uint64
+	BlocksAvailable uint64 // free blocks that non-root users can use
+	Files           uint64
+	FilesFree       uint64
+	NameLength      uint64 // the maximum length of a file name
+}
+
+// QuotaUsageRequest returns the storage quotas that apply to the user, and
+// how much of them is used. Quota administrators get every quota.
+type QuotaUsageRequest struct{}
+
+// QuotaUsage is a storage quota and its usage, in bytes. User is set for the
+// quota of a user, and Path for the quota of a directory.
+type QuotaUsage struct {
+	User  string
+	Path  string
+	Limit int64
+	Usage int64
+}
+
+type QuotaUsageResponse struct {
+	Quotas []QuotaUsage
+}
+
+type ChangeType int
+
+const (
+	ChangeCreated ChangeType = iota + 1
+	ChangeWritten
+	ChangeRenamed
+	ChangeDeleted
+	ChangeAttributes // the mode, the owner or the times of a file changed
+)
+
+// ChangeNotification is sent by the server when a client changes the shared
+// directory. It's sent to every other client, in a message with the
+// IsResponse flag turned off.
+type ChangeNotification struct {
+	Type    ChangeType
+	Path    string
+	NewPath string // only for ChangeRenamed
+}
+
 func Init() {
 	gob.Register(EmptyResponse{})
+	gob.Register(HelloRequest{})
+	gob.Register(HelloResponse{})
+	gob.Register(AuthenticateRequest{})
+	gob.Register(AuthenticateResponse{})
 	gob.Register(OpenFileRequest{})
 	gob.Register(OpenFileResponse{})
 	gob.Register(ReadFileRequest{})
@@ -121,4 +349,28 @@ func Init() {
 	gob.Register(DeleteFileRequest{})
 	gob.Register(WriteFileRequest{})
 	gob.Register(WriteFileResponse{})
+	gob.Register(CreateSymlinkRequest{})
+	gob.Register(ReadSymlinkRequest{})
+	gob.Register(ReadSymlinkResponse{})
+	gob.Register(CreateHardLinkRequest{})
+	gob.Register(GetExtendedAttributeRequest{})
+	gob.Register(GetExtendedAttributeResponse{})
+	gob.Register(ListExtendedAttributesRequest{})
+	gob.Register(ListExtendedAttributesResponse{})
+	gob.Register(SetExtendedAttributeRequest{})
+	gob.Register(RemoveExtendedAttributeRequest{})
+	gob.Register(LockRequest{})
+	gob.Register(UnlockRequest{})
+	gob.Register(TestLockRequest{})
+	gob.Register(TestLockResponse{})
+	gob.Register(ChangeModeRequest{})
+	gob.Register(ChangeOwnerRequest{})
+	gob.Register(ChangeTimesRequest{})
+	gob.Register(FsyncFileRequest{})
+	gob.Register(FsyncDirectoryRequest{})
+	gob.Register(StatfsRequest{})
+	gob.Register(StatfsResponse{})
+	gob.Register(QuotaUsageRequest{})
+	gob.Register(QuotaUsageResponse{})
+	gob.Register(ChangeNotification{})
 }
@@ -0,0 +1,5 @@
+// Package pb contains the messages of the protobuf encoding of the Filebox
+// protocol, generated from filebox.proto with protoc-gen-go v1.3.2.
+package pb
+
+//go:generate protoc --go_out=. filebox.proto
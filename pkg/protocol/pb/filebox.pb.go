@@ -0,0 +1,2848 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: filebox.proto
+
+package pb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type MessageType int32
+
+const (
+	MessageType_EMPTY_RESPONSE                    MessageType = 0
+	MessageType_HELLO_REQUEST                     MessageType = 1
+	MessageType_HELLO_RESPONSE                    MessageType = 2
+	MessageType_AUTHENTICATE_REQUEST              MessageType = 3
+	MessageType_AUTHENTICATE_RESPONSE             MessageType = 4
+	MessageType_OPEN_FILE_REQUEST                 MessageType = 5
+	MessageType_OPEN_FILE_RESPONSE                MessageType = 6
+	MessageType_READ_FILE_REQUEST                 MessageType = 7
+	MessageType_READ_FILE_RESPONSE                MessageType = 8
+	MessageType_READ_DIRECTORY_REQUEST            MessageType = 9
+	MessageType_READ_DIRECTORY_RESPONSE           MessageType = 10
+	MessageType_GET_FILE_ATTRIBUTES_REQUEST       MessageType = 11
+	MessageType_GET_FILE_ATTRIBUTES_RESPONSE      MessageType = 12
+	MessageType_CLOSE_FILE_REQUEST                MessageType = 13
+	MessageType_CREATE_DIRECTORY_REQUEST          MessageType = 14
+	MessageType_CREATE_FILE_REQUEST               MessageType = 15
+	MessageType_RENAME_REQUEST                    MessageType = 16
+	MessageType_DELETE_DIRECTORY_REQUEST          MessageType = 17
+	MessageType_TRUNCATE_REQUEST                  MessageType = 18
+	MessageType_DELETE_FILE_REQUEST               MessageType = 19
+	MessageType_WRITE_FILE_REQUEST                MessageType = 20
+	MessageType_WRITE_FILE_RESPONSE               MessageType = 21
+	MessageType_CHANGE_NOTIFICATION               MessageType = 22
+	MessageType_FSYNC_FILE_REQUEST                MessageType = 23
+	MessageType_FSYNC_DIRECTORY_REQUEST           MessageType = 24
+	MessageType_CHANGE_MODE_REQUEST               MessageType = 25
+	MessageType_CHANGE_OWNER_REQUEST              MessageType = 26
+	MessageType_CHANGE_TIMES_REQUEST              MessageType = 27
+	MessageType_CREATE_SYMLINK_REQUEST            MessageType = 28
+	MessageType_READ_SYMLINK_REQUEST              MessageType = 29
+	MessageType_READ_SYMLINK_RESPONSE             MessageType = 30
+	MessageType_CREATE_HARD_LINK_REQUEST          MessageType = 31
+	MessageType_GET_EXTENDED_ATTRIBUTE_REQUEST    MessageType = 32
+	MessageType_GET_EXTENDED_ATTRIBUTE_RESPONSE   MessageType = 33
+	MessageType_LIST_EXTENDED_ATTRIBUTES_REQUEST  MessageType = 34
+	MessageType_LIST_EXTENDED_ATTRIBUTES_RESPONSE MessageType = 35
+	MessageType_SET_EXTENDED_ATTRIBUTE_REQUEST    MessageType = 36
+	MessageType_REMOVE_EXTENDED_ATTRIBUTE_REQUEST MessageType = 37
+	MessageType_LOCK_REQUEST                      MessageType = 38
+	MessageType_UNLOCK_REQUEST                    MessageType = 39
+	MessageType_TEST_LOCK_REQUEST                 MessageType = 40
+	MessageType_TEST_LOCK_RESPONSE                MessageType = 41
+	MessageType_STATFS_REQUEST                    MessageType = 42
+	MessageType_STATFS_RESPONSE                   MessageType = 43
+	MessageType_QUOTA_USAGE_REQUEST               MessageType = 44
+	MessageType_QUOTA_USAGE_RESPONSE              MessageType = 45
+)
+
+var MessageType_name = map[int32]string{
+	0:  "EMPTY_RESPONSE",
+	1:  "HELLO_REQUEST",
+	2:  "HELLO_RESPONSE",
+	3:  "AUTHENTICATE_REQUEST",
+	4:  "AUTHENTICATE_RESPONSE",
+	5:  "OPEN_FILE_REQUEST",
+	6:  "OPEN_FILE_RESPONSE",
+	7:  "READ_FILE_REQUEST",
+	8:  "READ_FILE_RESPONSE",
+	9:  "READ_DIRECTORY_REQUEST",
+	10: "READ_DIRECTORY_RESPONSE",
+	11: "GET_FILE_ATTRIBUTES_REQUEST",
+	12: "GET_FILE_ATTRIBUTES_RESPONSE",
+	13: "CLOSE_FILE_REQUEST",
+	14: "CREATE_DIRECTORY_REQUEST",
+	15: "CREATE_FILE_REQUEST",
+	16: "RENAME_REQUEST",
+	17: "DELETE_DIRECTORY_REQUEST",
+	18: "TRUNCATE_REQUEST",
+	19: "DELETE_FILE_REQUEST",
+	20: "WRITE_FILE_REQUEST",
+	21: "WRITE_FILE_RESPONSE",
+	22: "CHANGE_NOTIFICATION",
+	23: "FSYNC_FILE_REQUEST",
+	24: "FSYNC_DIRECTORY_REQUEST",
+	25: "CHANGE_MODE_REQUEST",
+	26: "CHANGE_OWNER_REQUEST",
+	27: "CHANGE_TIMES_REQUEST",
+	28: "CREATE_SYMLINK_REQUEST",
+	29: "READ_SYMLINK_REQUEST",
+	30: "READ_SYMLINK_RESPONSE",
+	31: "CREATE_HARD_LINK_REQUEST",
+	32: "GET_EXTENDED_ATTRIBUTE_REQUEST",
+	33: "GET_EXTENDED_ATTRIBUTE_RESPONSE",
+	34: "LIST_EXTENDED_ATTRIBUTES_REQUEST",
+	35: "LIST_EXTENDED_ATTRIBUTES_RESPONSE",
+	36: "SET_EXTENDED_ATTRIBUTE_REQUEST",
+	37: "REMOVE_EXTENDED_ATTRIBUTE_REQUEST",
+	38: "LOCK_REQUEST",
+	39: "UNLOCK_REQUEST",
+	40: "TEST_LOCK_REQUEST",
+	41: "TEST_LOCK_RESPONSE",
+	42: "STATFS_REQUEST",
+	43: "STATFS_RESPONSE",
+	44: "QUOTA_USAGE_REQUEST",
+	45: "QUOTA_USAGE_RESPONSE",
+}
+
+var MessageType_value = map[string]int32{
+	"EMPTY_RESPONSE":                    0,
+	"HELLO_REQUEST":                     1,
+	"HELLO_RESPONSE":                    2,
+	"AUTHENTICATE_REQUEST":              3,
+	"AUTHENTICATE_RESPONSE":             4,
+	"OPEN_FILE_REQUEST":                 5,
+	"OPEN_FILE_RESPONSE":                6,
+	"READ_FILE_REQUEST":                 7,
+	"READ_FILE_RESPONSE":                8,
+	"READ_DIRECTORY_REQUEST":            9,
+	"READ_DIRECTORY_RESPONSE":           10,
+	"GET_FILE_ATTRIBUTES_REQUEST":       11,
+	"GET_FILE_ATTRIBUTES_RESPONSE":      12,
+	"CLOSE_FILE_REQUEST":                13,
+	"CREATE_DIRECTORY_REQUEST":          14,
+	"CREATE_FILE_REQUEST":               15,
+	"RENAME_REQUEST":                    16,
+	"DELETE_DIRECTORY_REQUEST":          17,
+	"TRUNCATE_REQUEST":                  18,
+	"DELETE_FILE_REQUEST":               19,
+	"WRITE_FILE_REQUEST":                20,
+	"WRITE_FILE_RESPONSE":               21,
+	"CHANGE_NOTIFICATION":               22,
+	"FSYNC_FILE_REQUEST":                23,
+	"FSYNC_DIRECTORY_REQUEST":           24,
+	"CHANGE_MODE_REQUEST":               25,
+	"CHANGE_OWNER_REQUEST":              26,
+	"CHANGE_TIMES_REQUEST":              27,
+	"CREATE_SYMLINK_REQUEST":            28,
+	"READ_SYMLINK_REQUEST":              29,
+	"READ_SYMLINK_RESPONSE":             30,
+	"CREATE_HARD_LINK_REQUEST":          31,
+	"GET_EXTENDED_ATTRIBUTE_REQUEST":    32,
+	"GET_EXTENDED_ATTRIBUTE_RESPONSE":   33,
+	"LIST_EXTENDED_ATTRIBUTES_REQUEST":  34,
+	"LIST_EXTENDED_ATTRIBUTES_RESPONSE": 35,
+	"SET_EXTENDED_ATTRIBUTE_REQUEST":    36,
+	"REMOVE_EXTENDED_ATTRIBUTE_REQUEST": 37,
+	"LOCK_REQUEST":                      38,
+	"UNLOCK_REQUEST":                    39,
+	"TEST_LOCK_REQUEST":                 40,
+	"TEST_LOCK_RESPONSE":                41,
+	"STATFS_REQUEST":                    42,
+	"STATFS_RESPONSE":                   43,
+	"QUOTA_USAGE_REQUEST":               44,
+	"QUOTA_USAGE_RESPONSE":              45,
+}
+
+func (x MessageType) String() string {
+	return proto.EnumName(MessageType_name, int32(x))
+}
+
+func (MessageType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{0}
+}
+
+// Envelope is the protobuf form of protocol.Message. data holds the encoded
+// request, response or notification, and type says which one it is.
+type Envelope struct {
+	MessageId            uint32      `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	IsResponse           bool        `protobuf:"varint,2,opt,name=is_response,json=isResponse,proto3" json:"is_response,omitempty"`
+	Type                 MessageType `protobuf:"varint,3,opt,name=type,proto3,enum=filebox.MessageType" json:"type,omitempty"`
+	Data                 []byte      `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	ErrorCode            uint32      `protobuf:"varint,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage         string      `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Envelope) Reset()         { *m = Envelope{} }
+func (m *Envelope) String() string { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()    {}
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{0}
+}
+
+func (m *Envelope) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Envelope.Unmarshal(m, b)
+}
+func (m *Envelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Envelope.Marshal(b, m, deterministic)
+}
+func (m *Envelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Envelope.Merge(m, src)
+}
+func (m *Envelope) XXX_Size() int {
+	return xxx_messageInfo_Envelope.Size(m)
+}
+func (m *Envelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_Envelope.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Envelope proto.InternalMessageInfo
+
+func (m *Envelope) GetMessageId() uint32 {
+	if m != nil {
+		return m.MessageId
+	}
+	return 0
+}
+
+func (m *Envelope) GetIsResponse() bool {
+	if m != nil {
+		return m.IsResponse
+	}
+	return false
+}
+
+func (m *Envelope) GetType() MessageType {
+	if m != nil {
+		return m.Type
+	}
+	return MessageType_EMPTY_RESPONSE
+}
+
+func (m *Envelope) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Envelope) GetErrorCode() uint32 {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return 0
+}
+
+func (m *Envelope) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+type EmptyResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EmptyResponse) Reset()         { *m = EmptyResponse{} }
+func (m *EmptyResponse) String() string { return proto.CompactTextString(m) }
+func (*EmptyResponse) ProtoMessage()    {}
+func (*EmptyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{1}
+}
+
+func (m *EmptyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EmptyResponse.Unmarshal(m, b)
+}
+func (m *EmptyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EmptyResponse.Marshal(b, m, deterministic)
+}
+func (m *EmptyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmptyResponse.Merge(m, src)
+}
+func (m *EmptyResponse) XXX_Size() int {
+	return xxx_messageInfo_EmptyResponse.Size(m)
+}
+func (m *EmptyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmptyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
+
+type FileInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size                 int64    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Mode                 uint32   `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	ModTime              int64    `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	IsDir                bool     `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Uid                  uint32   `protobuf:"varint,6,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid                  uint32   `protobuf:"varint,7,opt,name=gid,proto3" json:"gid,omitempty"`
+	AccessTime           int64    `protobuf:"varint,8,opt,name=access_time,json=accessTime,proto3" json:"access_time,omitempty"`
+	ChangeTime           int64    `protobuf:"varint,9,opt,name=change_time,json=changeTime,proto3" json:"change_time,omitempty"`
+	Nlink                uint32   `protobuf:"varint,10,opt,name=nlink,proto3" json:"nlink,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileInfo) Reset()         { *m = FileInfo{} }
+func (m *FileInfo) String() string { return proto.CompactTextString(m) }
+func (*FileInfo) ProtoMessage()    {}
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{2}
+}
+
+func (m *FileInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileInfo.Unmarshal(m, b)
+}
+func (m *FileInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileInfo.Marshal(b, m, deterministic)
+}
+func (m *FileInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileInfo.Merge(m, src)
+}
+func (m *FileInfo) XXX_Size() int {
+	return xxx_messageInfo_FileInfo.Size(m)
+}
+func (m *FileInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileInfo proto.InternalMessageInfo
+
+func (m *FileInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FileInfo) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *FileInfo) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileInfo) GetModTime() int64 {
+	if m != nil {
+		return m.ModTime
+	}
+	return 0
+}
+
+func (m *FileInfo) GetIsDir() bool {
+	if m != nil {
+		return m.IsDir
+	}
+	return false
+}
+
+func (m *FileInfo) GetUid() uint32 {
+	if m != nil {
+		return m.Uid
+	}
+	return 0
+}
+
+func (m *FileInfo) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
+}
+
+func (m *FileInfo) GetAccessTime() int64 {
+	if m != nil {
+		return m.AccessTime
+	}
+	return 0
+}
+
+func (m *FileInfo) GetChangeTime() int64 {
+	if m != nil {
+		return m.ChangeTime
+	}
+	return 0
+}
+
+func (m *FileInfo) GetNlink() uint32 {
+	if m != nil {
+		return m.Nlink
+	}
+	return 0
+}
+
+type HelloRequest struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	MinVersion           uint32   `protobuf:"varint,2,opt,name=min_version,json=minVersion,proto3" json:"min_version,omitempty"`
+	Capabilities         []string `protobuf:"bytes,3,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HelloRequest) Reset()         { *m = HelloRequest{} }
+func (m *HelloRequest) String() string { return proto.CompactTextString(m) }
+func (*HelloRequest) ProtoMessage()    {}
+func (*HelloRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{3}
+}
+
+func (m *HelloRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HelloRequest.Unmarshal(m, b)
+}
+func (m *HelloRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HelloRequest.Marshal(b, m, deterministic)
+}
+func (m *HelloRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HelloRequest.Merge(m, src)
+}
+func (m *HelloRequest) XXX_Size() int {
+	return xxx_messageInfo_HelloRequest.Size(m)
+}
+func (m *HelloRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HelloRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HelloRequest proto.InternalMessageInfo
+
+func (m *HelloRequest) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HelloRequest) GetMinVersion() uint32 {
+	if m != nil {
+		return m.MinVersion
+	}
+	return 0
+}
+
+func (m *HelloRequest) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type HelloResponse struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities         []string `protobuf:"bytes,2,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HelloResponse) Reset()         { *m = HelloResponse{} }
+func (m *HelloResponse) String() string { return proto.CompactTextString(m) }
+func (*HelloResponse) ProtoMessage()    {}
+func (*HelloResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{4}
+}
+
+func (m *HelloResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HelloResponse.Unmarshal(m, b)
+}
+func (m *HelloResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HelloResponse.Marshal(b, m, deterministic)
+}
+func (m *HelloResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HelloResponse.Merge(m, src)
+}
+func (m *HelloResponse) XXX_Size() int {
+	return xxx_messageInfo_HelloResponse.Size(m)
+}
+func (m *HelloResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HelloResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HelloResponse proto.InternalMessageInfo
+
+func (m *HelloResponse) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HelloResponse) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type AuthenticateRequest struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthenticateRequest) Reset()         { *m = AuthenticateRequest{} }
+func (m *AuthenticateRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateRequest) ProtoMessage()    {}
+func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{5}
+}
+
+func (m *AuthenticateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateRequest.Unmarshal(m, b)
+}
+func (m *AuthenticateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateRequest.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateRequest.Merge(m, src)
+}
+func (m *AuthenticateRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateRequest.Size(m)
+}
+func (m *AuthenticateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateRequest proto.InternalMessageInfo
+
+func (m *AuthenticateRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *AuthenticateRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type AuthenticateResponse struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthenticateResponse) Reset()         { *m = AuthenticateResponse{} }
+func (m *AuthenticateResponse) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateResponse) ProtoMessage()    {}
+func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{6}
+}
+
+func (m *AuthenticateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateResponse.Unmarshal(m, b)
+}
+func (m *AuthenticateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateResponse.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateResponse.Merge(m, src)
+}
+func (m *AuthenticateResponse) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateResponse.Size(m)
+}
+func (m *AuthenticateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateResponse proto.InternalMessageInfo
+
+func (m *AuthenticateResponse) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type OpenFileRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Flags                int64    `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OpenFileRequest) Reset()         { *m = OpenFileRequest{} }
+func (m *OpenFileRequest) String() string { return proto.CompactTextString(m) }
+func (*OpenFileRequest) ProtoMessage()    {}
+func (*OpenFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{7}
+}
+
+func (m *OpenFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OpenFileRequest.Unmarshal(m, b)
+}
+func (m *OpenFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OpenFileRequest.Marshal(b, m, deterministic)
+}
+func (m *OpenFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OpenFileRequest.Merge(m, src)
+}
+func (m *OpenFileRequest) XXX_Size() int {
+	return xxx_messageInfo_OpenFileRequest.Size(m)
+}
+func (m *OpenFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OpenFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OpenFileRequest proto.InternalMessageInfo
+
+func (m *OpenFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *OpenFileRequest) GetFlags() int64 {
+	if m != nil {
+		return m.Flags
+	}
+	return 0
+}
+
+type OpenFileResponse struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OpenFileResponse) Reset()         { *m = OpenFileResponse{} }
+func (m *OpenFileResponse) String() string { return proto.CompactTextString(m) }
+func (*OpenFileResponse) ProtoMessage()    {}
+func (*OpenFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{8}
+}
+
+func (m *OpenFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OpenFileResponse.Unmarshal(m, b)
+}
+func (m *OpenFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OpenFileResponse.Marshal(b, m, deterministic)
+}
+func (m *OpenFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OpenFileResponse.Merge(m, src)
+}
+func (m *OpenFileResponse) XXX_Size() int {
+	return xxx_messageInfo_OpenFileResponse.Size(m)
+}
+func (m *OpenFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OpenFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OpenFileResponse proto.InternalMessageInfo
+
+func (m *OpenFileResponse) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+type ReadFileRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadFileRequest) Reset()         { *m = ReadFileRequest{} }
+func (m *ReadFileRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadFileRequest) ProtoMessage()    {}
+func (*ReadFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{9}
+}
+
+func (m *ReadFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadFileRequest.Unmarshal(m, b)
+}
+func (m *ReadFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadFileRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadFileRequest.Merge(m, src)
+}
+func (m *ReadFileRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadFileRequest.Size(m)
+}
+func (m *ReadFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadFileRequest proto.InternalMessageInfo
+
+func (m *ReadFileRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *ReadFileRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ReadFileRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type ReadFileResponse struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	BytesRead            int64    `protobuf:"varint,2,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadFileResponse) Reset()         { *m = ReadFileResponse{} }
+func (m *ReadFileResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadFileResponse) ProtoMessage()    {}
+func (*ReadFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{10}
+}
+
+func (m *ReadFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadFileResponse.Unmarshal(m, b)
+}
+func (m *ReadFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadFileResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadFileResponse.Merge(m, src)
+}
+func (m *ReadFileResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadFileResponse.Size(m)
+}
+func (m *ReadFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadFileResponse proto.InternalMessageInfo
+
+func (m *ReadFileResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ReadFileResponse) GetBytesRead() int64 {
+	if m != nil {
+		return m.BytesRead
+	}
+	return 0
+}
+
+type ReadDirectoryRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	After                string   `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadDirectoryRequest) Reset()         { *m = ReadDirectoryRequest{} }
+func (m *ReadDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadDirectoryRequest) ProtoMessage()    {}
+func (*ReadDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{11}
+}
+
+func (m *ReadDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadDirectoryRequest.Unmarshal(m, b)
+}
+func (m *ReadDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadDirectoryRequest.Merge(m, src)
+}
+func (m *ReadDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadDirectoryRequest.Size(m)
+}
+func (m *ReadDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadDirectoryRequest proto.InternalMessageInfo
+
+func (m *ReadDirectoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ReadDirectoryRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *ReadDirectoryRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ReadDirectoryResponse struct {
+	Files                []*FileInfo `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	More                 bool        `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ReadDirectoryResponse) Reset()         { *m = ReadDirectoryResponse{} }
+func (m *ReadDirectoryResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadDirectoryResponse) ProtoMessage()    {}
+func (*ReadDirectoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{12}
+}
+
+func (m *ReadDirectoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadDirectoryResponse.Unmarshal(m, b)
+}
+func (m *ReadDirectoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadDirectoryResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadDirectoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadDirectoryResponse.Merge(m, src)
+}
+func (m *ReadDirectoryResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadDirectoryResponse.Size(m)
+}
+func (m *ReadDirectoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadDirectoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadDirectoryResponse proto.InternalMessageInfo
+
+func (m *ReadDirectoryResponse) GetFiles() []*FileInfo {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+func (m *ReadDirectoryResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type GetFileAttributesRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	FileHandle           uint64   `protobuf:"varint,2,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetFileAttributesRequest) Reset()         { *m = GetFileAttributesRequest{} }
+func (m *GetFileAttributesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFileAttributesRequest) ProtoMessage()    {}
+func (*GetFileAttributesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{13}
+}
+
+func (m *GetFileAttributesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFileAttributesRequest.Unmarshal(m, b)
+}
+func (m *GetFileAttributesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFileAttributesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFileAttributesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFileAttributesRequest.Merge(m, src)
+}
+func (m *GetFileAttributesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFileAttributesRequest.Size(m)
+}
+func (m *GetFileAttributesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFileAttributesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFileAttributesRequest proto.InternalMessageInfo
+
+func (m *GetFileAttributesRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GetFileAttributesRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+type GetFileAttributesResponse struct {
+	FileInfo             *FileInfo `protobuf:"bytes,1,opt,name=file_info,json=fileInfo,proto3" json:"file_info,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetFileAttributesResponse) Reset()         { *m = GetFileAttributesResponse{} }
+func (m *GetFileAttributesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetFileAttributesResponse) ProtoMessage()    {}
+func (*GetFileAttributesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{14}
+}
+
+func (m *GetFileAttributesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFileAttributesResponse.Unmarshal(m, b)
+}
+func (m *GetFileAttributesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFileAttributesResponse.Marshal(b, m, deterministic)
+}
+func (m *GetFileAttributesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFileAttributesResponse.Merge(m, src)
+}
+func (m *GetFileAttributesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetFileAttributesResponse.Size(m)
+}
+func (m *GetFileAttributesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFileAttributesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFileAttributesResponse proto.InternalMessageInfo
+
+func (m *GetFileAttributesResponse) GetFileInfo() *FileInfo {
+	if m != nil {
+		return m.FileInfo
+	}
+	return nil
+}
+
+type CloseFileRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloseFileRequest) Reset()         { *m = CloseFileRequest{} }
+func (m *CloseFileRequest) String() string { return proto.CompactTextString(m) }
+func (*CloseFileRequest) ProtoMessage()    {}
+func (*CloseFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{15}
+}
+
+func (m *CloseFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloseFileRequest.Unmarshal(m, b)
+}
+func (m *CloseFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloseFileRequest.Marshal(b, m, deterministic)
+}
+func (m *CloseFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloseFileRequest.Merge(m, src)
+}
+func (m *CloseFileRequest) XXX_Size() int {
+	return xxx_messageInfo_CloseFileRequest.Size(m)
+}
+func (m *CloseFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloseFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloseFileRequest proto.InternalMessageInfo
+
+func (m *CloseFileRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+type CreateDirectoryRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode                 uint32   `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateDirectoryRequest) Reset()         { *m = CreateDirectoryRequest{} }
+func (m *CreateDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateDirectoryRequest) ProtoMessage()    {}
+func (*CreateDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{16}
+}
+
+func (m *CreateDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateDirectoryRequest.Unmarshal(m, b)
+}
+func (m *CreateDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDirectoryRequest.Merge(m, src)
+}
+func (m *CreateDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateDirectoryRequest.Size(m)
+}
+func (m *CreateDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateDirectoryRequest proto.InternalMessageInfo
+
+func (m *CreateDirectoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CreateDirectoryRequest) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+type CreateFileRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateFileRequest) Reset()         { *m = CreateFileRequest{} }
+func (m *CreateFileRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateFileRequest) ProtoMessage()    {}
+func (*CreateFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{17}
+}
+
+func (m *CreateFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateFileRequest.Unmarshal(m, b)
+}
+func (m *CreateFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateFileRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateFileRequest.Merge(m, src)
+}
+func (m *CreateFileRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateFileRequest.Size(m)
+}
+func (m *CreateFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateFileRequest proto.InternalMessageInfo
+
+func (m *CreateFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type RenameRequest struct {
+	OldPath              string   `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath              string   `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameRequest) Reset()         { *m = RenameRequest{} }
+func (m *RenameRequest) String() string { return proto.CompactTextString(m) }
+func (*RenameRequest) ProtoMessage()    {}
+func (*RenameRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{18}
+}
+
+func (m *RenameRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenameRequest.Unmarshal(m, b)
+}
+func (m *RenameRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenameRequest.Marshal(b, m, deterministic)
+}
+func (m *RenameRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameRequest.Merge(m, src)
+}
+func (m *RenameRequest) XXX_Size() int {
+	return xxx_messageInfo_RenameRequest.Size(m)
+}
+func (m *RenameRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameRequest proto.InternalMessageInfo
+
+func (m *RenameRequest) GetOldPath() string {
+	if m != nil {
+		return m.OldPath
+	}
+	return ""
+}
+
+func (m *RenameRequest) GetNewPath() string {
+	if m != nil {
+		return m.NewPath
+	}
+	return ""
+}
+
+type DeleteDirectoryRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteDirectoryRequest) Reset()         { *m = DeleteDirectoryRequest{} }
+func (m *DeleteDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteDirectoryRequest) ProtoMessage()    {}
+func (*DeleteDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{19}
+}
+
+func (m *DeleteDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteDirectoryRequest.Unmarshal(m, b)
+}
+func (m *DeleteDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDirectoryRequest.Merge(m, src)
+}
+func (m *DeleteDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteDirectoryRequest.Size(m)
+}
+func (m *DeleteDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteDirectoryRequest proto.InternalMessageInfo
+
+func (m *DeleteDirectoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type TruncateRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	FileHandle           uint64   `protobuf:"varint,2,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TruncateRequest) Reset()         { *m = TruncateRequest{} }
+func (m *TruncateRequest) String() string { return proto.CompactTextString(m) }
+func (*TruncateRequest) ProtoMessage()    {}
+func (*TruncateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{20}
+}
+
+func (m *TruncateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TruncateRequest.Unmarshal(m, b)
+}
+func (m *TruncateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TruncateRequest.Marshal(b, m, deterministic)
+}
+func (m *TruncateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TruncateRequest.Merge(m, src)
+}
+func (m *TruncateRequest) XXX_Size() int {
+	return xxx_messageInfo_TruncateRequest.Size(m)
+}
+func (m *TruncateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TruncateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TruncateRequest proto.InternalMessageInfo
+
+func (m *TruncateRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TruncateRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *TruncateRequest) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type DeleteFileRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteFileRequest) Reset()         { *m = DeleteFileRequest{} }
+func (m *DeleteFileRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteFileRequest) ProtoMessage()    {}
+func (*DeleteFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{21}
+}
+
+func (m *DeleteFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteFileRequest.Unmarshal(m, b)
+}
+func (m *DeleteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteFileRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFileRequest.Merge(m, src)
+}
+func (m *DeleteFileRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteFileRequest.Size(m)
+}
+func (m *DeleteFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFileRequest proto.InternalMessageInfo
+
+func (m *DeleteFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type WriteFileRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteFileRequest) Reset()         { *m = WriteFileRequest{} }
+func (m *WriteFileRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteFileRequest) ProtoMessage()    {}
+func (*WriteFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{22}
+}
+
+func (m *WriteFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteFileRequest.Unmarshal(m, b)
+}
+func (m *WriteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteFileRequest.Marshal(b, m, deterministic)
+}
+func (m *WriteFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteFileRequest.Merge(m, src)
+}
+func (m *WriteFileRequest) XXX_Size() int {
+	return xxx_messageInfo_WriteFileRequest.Size(m)
+}
+func (m *WriteFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteFileRequest proto.InternalMessageInfo
+
+func (m *WriteFileRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *WriteFileRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *WriteFileRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type WriteFileResponse struct {
+	BytesWritten         int64    `protobuf:"varint,1,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteFileResponse) Reset()         { *m = WriteFileResponse{} }
+func (m *WriteFileResponse) String() string { return proto.CompactTextString(m) }
+func (*WriteFileResponse) ProtoMessage()    {}
+func (*WriteFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{23}
+}
+
+func (m *WriteFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteFileResponse.Unmarshal(m, b)
+}
+func (m *WriteFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteFileResponse.Marshal(b, m, deterministic)
+}
+func (m *WriteFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteFileResponse.Merge(m, src)
+}
+func (m *WriteFileResponse) XXX_Size() int {
+	return xxx_messageInfo_WriteFileResponse.Size(m)
+}
+func (m *WriteFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteFileResponse proto.InternalMessageInfo
+
+func (m *WriteFileResponse) GetBytesWritten() int64 {
+	if m != nil {
+		return m.BytesWritten
+	}
+	return 0
+}
+
+type CreateSymlinkRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSymlinkRequest) Reset()         { *m = CreateSymlinkRequest{} }
+func (m *CreateSymlinkRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSymlinkRequest) ProtoMessage()    {}
+func (*CreateSymlinkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{24}
+}
+
+func (m *CreateSymlinkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSymlinkRequest.Unmarshal(m, b)
+}
+func (m *CreateSymlinkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSymlinkRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSymlinkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSymlinkRequest.Merge(m, src)
+}
+func (m *CreateSymlinkRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSymlinkRequest.Size(m)
+}
+func (m *CreateSymlinkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSymlinkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSymlinkRequest proto.InternalMessageInfo
+
+func (m *CreateSymlinkRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CreateSymlinkRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+type ReadSymlinkRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSymlinkRequest) Reset()         { *m = ReadSymlinkRequest{} }
+func (m *ReadSymlinkRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadSymlinkRequest) ProtoMessage()    {}
+func (*ReadSymlinkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{25}
+}
+
+func (m *ReadSymlinkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSymlinkRequest.Unmarshal(m, b)
+}
+func (m *ReadSymlinkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSymlinkRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadSymlinkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSymlinkRequest.Merge(m, src)
+}
+func (m *ReadSymlinkRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadSymlinkRequest.Size(m)
+}
+func (m *ReadSymlinkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSymlinkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSymlinkRequest proto.InternalMessageInfo
+
+func (m *ReadSymlinkRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type ReadSymlinkResponse struct {
+	Target               string   `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSymlinkResponse) Reset()         { *m = ReadSymlinkResponse{} }
+func (m *ReadSymlinkResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadSymlinkResponse) ProtoMessage()    {}
+func (*ReadSymlinkResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{26}
+}
+
+func (m *ReadSymlinkResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSymlinkResponse.Unmarshal(m, b)
+}
+func (m *ReadSymlinkResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSymlinkResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadSymlinkResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSymlinkResponse.Merge(m, src)
+}
+func (m *ReadSymlinkResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadSymlinkResponse.Size(m)
+}
+func (m *ReadSymlinkResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSymlinkResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSymlinkResponse proto.InternalMessageInfo
+
+func (m *ReadSymlinkResponse) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+type CreateHardLinkRequest struct {
+	OldPath              string   `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath              string   `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateHardLinkRequest) Reset()         { *m = CreateHardLinkRequest{} }
+func (m *CreateHardLinkRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateHardLinkRequest) ProtoMessage()    {}
+func (*CreateHardLinkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{27}
+}
+
+func (m *CreateHardLinkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateHardLinkRequest.Unmarshal(m, b)
+}
+func (m *CreateHardLinkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateHardLinkRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateHardLinkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateHardLinkRequest.Merge(m, src)
+}
+func (m *CreateHardLinkRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateHardLinkRequest.Size(m)
+}
+func (m *CreateHardLinkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateHardLinkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateHardLinkRequest proto.InternalMessageInfo
+
+func (m *CreateHardLinkRequest) GetOldPath() string {
+	if m != nil {
+		return m.OldPath
+	}
+	return ""
+}
+
+func (m *CreateHardLinkRequest) GetNewPath() string {
+	if m != nil {
+		return m.NewPath
+	}
+	return ""
+}
+
+type GetExtendedAttributeRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetExtendedAttributeRequest) Reset()         { *m = GetExtendedAttributeRequest{} }
+func (m *GetExtendedAttributeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetExtendedAttributeRequest) ProtoMessage()    {}
+func (*GetExtendedAttributeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{28}
+}
+
+func (m *GetExtendedAttributeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetExtendedAttributeRequest.Unmarshal(m, b)
+}
+func (m *GetExtendedAttributeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetExtendedAttributeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetExtendedAttributeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetExtendedAttributeRequest.Merge(m, src)
+}
+func (m *GetExtendedAttributeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetExtendedAttributeRequest.Size(m)
+}
+func (m *GetExtendedAttributeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetExtendedAttributeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetExtendedAttributeRequest proto.InternalMessageInfo
+
+func (m *GetExtendedAttributeRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GetExtendedAttributeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetExtendedAttributeResponse struct {
+	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetExtendedAttributeResponse) Reset()         { *m = GetExtendedAttributeResponse{} }
+func (m *GetExtendedAttributeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetExtendedAttributeResponse) ProtoMessage()    {}
+func (*GetExtendedAttributeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{29}
+}
+
+func (m *GetExtendedAttributeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetExtendedAttributeResponse.Unmarshal(m, b)
+}
+func (m *GetExtendedAttributeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetExtendedAttributeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetExtendedAttributeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetExtendedAttributeResponse.Merge(m, src)
+}
+func (m *GetExtendedAttributeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetExtendedAttributeResponse.Size(m)
+}
+func (m *GetExtendedAttributeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetExtendedAttributeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetExtendedAttributeResponse proto.InternalMessageInfo
+
+func (m *GetExtendedAttributeResponse) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type ListExtendedAttributesRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListExtendedAttributesRequest) Reset()         { *m = ListExtendedAttributesRequest{} }
+func (m *ListExtendedAttributesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListExtendedAttributesRequest) ProtoMessage()    {}
+func (*ListExtendedAttributesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{30}
+}
+
+func (m *ListExtendedAttributesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListExtendedAttributesRequest.Unmarshal(m, b)
+}
+func (m *ListExtendedAttributesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListExtendedAttributesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListExtendedAttributesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListExtendedAttributesRequest.Merge(m, src)
+}
+func (m *ListExtendedAttributesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListExtendedAttributesRequest.Size(m)
+}
+func (m *ListExtendedAttributesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListExtendedAttributesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListExtendedAttributesRequest proto.InternalMessageInfo
+
+func (m *ListExtendedAttributesRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type ListExtendedAttributesResponse struct {
+	Names                []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListExtendedAttributesResponse) Reset()         { *m = ListExtendedAttributesResponse{} }
+func (m *ListExtendedAttributesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListExtendedAttributesResponse) ProtoMessage()    {}
+func (*ListExtendedAttributesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{31}
+}
+
+func (m *ListExtendedAttributesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListExtendedAttributesResponse.Unmarshal(m, b)
+}
+func (m *ListExtendedAttributesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListExtendedAttributesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListExtendedAttributesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListExtendedAttributesResponse.Merge(m, src)
+}
+func (m *ListExtendedAttributesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListExtendedAttributesResponse.Size(m)
+}
+func (m *ListExtendedAttributesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListExtendedAttributesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListExtendedAttributesResponse proto.InternalMessageInfo
+
+func (m *ListExtendedAttributesResponse) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type SetExtendedAttributeRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value                []byte   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Flags                int64    `protobuf:"varint,4,opt,name=flags,proto3" json:"flags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetExtendedAttributeRequest) Reset()         { *m = SetExtendedAttributeRequest{} }
+func (m *SetExtendedAttributeRequest) String() string { return proto.CompactTextString(m) }
+func (*SetExtendedAttributeRequest) ProtoMessage()    {}
+func (*SetExtendedAttributeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{32}
+}
+
+func (m *SetExtendedAttributeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetExtendedAttributeRequest.Unmarshal(m, b)
+}
+func (m *SetExtendedAttributeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetExtendedAttributeRequest.Marshal(b, m, deterministic)
+}
+func (m *SetExtendedAttributeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetExtendedAttributeRequest.Merge(m, src)
+}
+func (m *SetExtendedAttributeRequest) XXX_Size() int {
+	return xxx_messageInfo_SetExtendedAttributeRequest.Size(m)
+}
+func (m *SetExtendedAttributeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetExtendedAttributeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetExtendedAttributeRequest proto.InternalMessageInfo
+
+func (m *SetExtendedAttributeRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SetExtendedAttributeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetExtendedAttributeRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *SetExtendedAttributeRequest) GetFlags() int64 {
+	if m != nil {
+		return m.Flags
+	}
+	return 0
+}
+
+type RemoveExtendedAttributeRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveExtendedAttributeRequest) Reset()         { *m = RemoveExtendedAttributeRequest{} }
+func (m *RemoveExtendedAttributeRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveExtendedAttributeRequest) ProtoMessage()    {}
+func (*RemoveExtendedAttributeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{33}
+}
+
+func (m *RemoveExtendedAttributeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveExtendedAttributeRequest.Unmarshal(m, b)
+}
+func (m *RemoveExtendedAttributeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveExtendedAttributeRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveExtendedAttributeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveExtendedAttributeRequest.Merge(m, src)
+}
+func (m *RemoveExtendedAttributeRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveExtendedAttributeRequest.Size(m)
+}
+func (m *RemoveExtendedAttributeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveExtendedAttributeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveExtendedAttributeRequest proto.InternalMessageInfo
+
+func (m *RemoveExtendedAttributeRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RemoveExtendedAttributeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// kind: 1 for byte range locks (fcntl), 2 for whole file locks (flock).
+// type: 0 for none, 1 for shared, 2 for exclusive.
+type LockRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Kind                 int64    `protobuf:"varint,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Type                 int64    `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	Start                int64    `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+	Length               int64    `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	Owner                uint64   `protobuf:"varint,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LockRequest) Reset()         { *m = LockRequest{} }
+func (m *LockRequest) String() string { return proto.CompactTextString(m) }
+func (*LockRequest) ProtoMessage()    {}
+func (*LockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{34}
+}
+
+func (m *LockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockRequest.Unmarshal(m, b)
+}
+func (m *LockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockRequest.Marshal(b, m, deterministic)
+}
+func (m *LockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockRequest.Merge(m, src)
+}
+func (m *LockRequest) XXX_Size() int {
+	return xxx_messageInfo_LockRequest.Size(m)
+}
+func (m *LockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockRequest proto.InternalMessageInfo
+
+func (m *LockRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *LockRequest) GetKind() int64 {
+	if m != nil {
+		return m.Kind
+	}
+	return 0
+}
+
+func (m *LockRequest) GetType() int64 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *LockRequest) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *LockRequest) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *LockRequest) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+type UnlockRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Kind                 int64    `protobuf:"varint,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Start                int64    `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	Length               int64    `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	Owner                uint64   `protobuf:"varint,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlockRequest) Reset()         { *m = UnlockRequest{} }
+func (m *UnlockRequest) String() string { return proto.CompactTextString(m) }
+func (*UnlockRequest) ProtoMessage()    {}
+func (*UnlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{35}
+}
+
+func (m *UnlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockRequest.Unmarshal(m, b)
+}
+func (m *UnlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockRequest.Marshal(b, m, deterministic)
+}
+func (m *UnlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockRequest.Merge(m, src)
+}
+func (m *UnlockRequest) XXX_Size() int {
+	return xxx_messageInfo_UnlockRequest.Size(m)
+}
+func (m *UnlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlockRequest proto.InternalMessageInfo
+
+func (m *UnlockRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *UnlockRequest) GetKind() int64 {
+	if m != nil {
+		return m.Kind
+	}
+	return 0
+}
+
+func (m *UnlockRequest) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *UnlockRequest) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *UnlockRequest) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+type TestLockRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	Kind                 int64    `protobuf:"varint,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Type                 int64    `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	Start                int64    `protobuf:"varint,4,opt,name=start,proto3" json:"start,omitempty"`
+	Length               int64    `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	Owner                uint64   `protobuf:"varint,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestLockRequest) Reset()         { *m = TestLockRequest{} }
+func (m *TestLockRequest) String() string { return proto.CompactTextString(m) }
+func (*TestLockRequest) ProtoMessage()    {}
+func (*TestLockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{36}
+}
+
+func (m *TestLockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestLockRequest.Unmarshal(m, b)
+}
+func (m *TestLockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestLockRequest.Marshal(b, m, deterministic)
+}
+func (m *TestLockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestLockRequest.Merge(m, src)
+}
+func (m *TestLockRequest) XXX_Size() int {
+	return xxx_messageInfo_TestLockRequest.Size(m)
+}
+func (m *TestLockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestLockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestLockRequest proto.InternalMessageInfo
+
+func (m *TestLockRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+func (m *TestLockRequest) GetKind() int64 {
+	if m != nil {
+		return m.Kind
+	}
+	return 0
+}
+
+func (m *TestLockRequest) GetType() int64 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *TestLockRequest) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *TestLockRequest) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *TestLockRequest) GetOwner() uint64 {
+	if m != nil {
+		return m.Owner
+	}
+	return 0
+}
+
+type TestLockResponse struct {
+	Type                 int64    `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Start                int64    `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	Length               int64    `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestLockResponse) Reset()         { *m = TestLockResponse{} }
+func (m *TestLockResponse) String() string { return proto.CompactTextString(m) }
+func (*TestLockResponse) ProtoMessage()    {}
+func (*TestLockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{37}
+}
+
+func (m *TestLockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestLockResponse.Unmarshal(m, b)
+}
+func (m *TestLockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestLockResponse.Marshal(b, m, deterministic)
+}
+func (m *TestLockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestLockResponse.Merge(m, src)
+}
+func (m *TestLockResponse) XXX_Size() int {
+	return xxx_messageInfo_TestLockResponse.Size(m)
+}
+func (m *TestLockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestLockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestLockResponse proto.InternalMessageInfo
+
+func (m *TestLockResponse) GetType() int64 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *TestLockResponse) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *TestLockResponse) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+type ChangeModeRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode                 uint32   `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeModeRequest) Reset()         { *m = ChangeModeRequest{} }
+func (m *ChangeModeRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeModeRequest) ProtoMessage()    {}
+func (*ChangeModeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{38}
+}
+
+func (m *ChangeModeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeModeRequest.Unmarshal(m, b)
+}
+func (m *ChangeModeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeModeRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeModeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeModeRequest.Merge(m, src)
+}
+func (m *ChangeModeRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeModeRequest.Size(m)
+}
+func (m *ChangeModeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeModeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeModeRequest proto.InternalMessageInfo
+
+func (m *ChangeModeRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ChangeModeRequest) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+type ChangeOwnerRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Uid                  int64    `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid                  int64    `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeOwnerRequest) Reset()         { *m = ChangeOwnerRequest{} }
+func (m *ChangeOwnerRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeOwnerRequest) ProtoMessage()    {}
+func (*ChangeOwnerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{39}
+}
+
+func (m *ChangeOwnerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeOwnerRequest.Unmarshal(m, b)
+}
+func (m *ChangeOwnerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeOwnerRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeOwnerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeOwnerRequest.Merge(m, src)
+}
+func (m *ChangeOwnerRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeOwnerRequest.Size(m)
+}
+func (m *ChangeOwnerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeOwnerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeOwnerRequest proto.InternalMessageInfo
+
+func (m *ChangeOwnerRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ChangeOwnerRequest) GetUid() int64 {
+	if m != nil {
+		return m.Uid
+	}
+	return 0
+}
+
+func (m *ChangeOwnerRequest) GetGid() int64 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
+}
+
+type ChangeTimesRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	AccessTime           int64    `protobuf:"varint,2,opt,name=access_time,json=accessTime,proto3" json:"access_time,omitempty"`
+	ModTime              int64    `protobuf:"varint,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeTimesRequest) Reset()         { *m = ChangeTimesRequest{} }
+func (m *ChangeTimesRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangeTimesRequest) ProtoMessage()    {}
+func (*ChangeTimesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{40}
+}
+
+func (m *ChangeTimesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeTimesRequest.Unmarshal(m, b)
+}
+func (m *ChangeTimesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeTimesRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangeTimesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeTimesRequest.Merge(m, src)
+}
+func (m *ChangeTimesRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangeTimesRequest.Size(m)
+}
+func (m *ChangeTimesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeTimesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeTimesRequest proto.InternalMessageInfo
+
+func (m *ChangeTimesRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ChangeTimesRequest) GetAccessTime() int64 {
+	if m != nil {
+		return m.AccessTime
+	}
+	return 0
+}
+
+func (m *ChangeTimesRequest) GetModTime() int64 {
+	if m != nil {
+		return m.ModTime
+	}
+	return 0
+}
+
+type FsyncFileRequest struct {
+	FileHandle           uint64   `protobuf:"varint,1,opt,name=file_handle,json=fileHandle,proto3" json:"file_handle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FsyncFileRequest) Reset()         { *m = FsyncFileRequest{} }
+func (m *FsyncFileRequest) String() string { return proto.CompactTextString(m) }
+func (*FsyncFileRequest) ProtoMessage()    {}
+func (*FsyncFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{41}
+}
+
+func (m *FsyncFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FsyncFileRequest.Unmarshal(m, b)
+}
+func (m *FsyncFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FsyncFileRequest.Marshal(b, m, deterministic)
+}
+func (m *FsyncFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FsyncFileRequest.Merge(m, src)
+}
+func (m *FsyncFileRequest) XXX_Size() int {
+	return xxx_messageInfo_FsyncFileRequest.Size(m)
+}
+func (m *FsyncFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FsyncFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FsyncFileRequest proto.InternalMessageInfo
+
+func (m *FsyncFileRequest) GetFileHandle() uint64 {
+	if m != nil {
+		return m.FileHandle
+	}
+	return 0
+}
+
+type FsyncDirectoryRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FsyncDirectoryRequest) Reset()         { *m = FsyncDirectoryRequest{} }
+func (m *FsyncDirectoryRequest) String() string { return proto.CompactTextString(m) }
+func (*FsyncDirectoryRequest) ProtoMessage()    {}
+func (*FsyncDirectoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{42}
+}
+
+func (m *FsyncDirectoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FsyncDirectoryRequest.Unmarshal(m, b)
+}
+func (m *FsyncDirectoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FsyncDirectoryRequest.Marshal(b, m, deterministic)
+}
+func (m *FsyncDirectoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FsyncDirectoryRequest.Merge(m, src)
+}
+func (m *FsyncDirectoryRequest) XXX_Size() int {
+	return xxx_messageInfo_FsyncDirectoryRequest.Size(m)
+}
+func (m *FsyncDirectoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FsyncDirectoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FsyncDirectoryRequest proto.InternalMessageInfo
+
+func (m *FsyncDirectoryRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type StatfsRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatfsRequest) Reset()         { *m = StatfsRequest{} }
+func (m *StatfsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatfsRequest) ProtoMessage()    {}
+func (*StatfsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{43}
+}
+
+func (m *StatfsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatfsRequest.Unmarshal(m, b)
+}
+func (m *StatfsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatfsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatfsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatfsRequest.Merge(m, src)
+}
+func (m *StatfsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatfsRequest.Size(m)
+}
+func (m *StatfsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatfsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatfsRequest proto.InternalMessageInfo
+
+func (m *StatfsRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+type StatfsResponse struct {
+	BlockSize            uint64   `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	Blocks               uint64   `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	BlocksFree           uint64   `protobuf:"varint,3,opt,name=blocks_free,json=blocksFree,proto3" json:"blocks_free,omitempty"`
+	BlocksAvailable      uint64   `protobuf:"varint,4,opt,name=blocks_available,json=blocksAvailable,proto3" json:"blocks_available,omitempty"`
+	Files                uint64   `protobuf:"varint,5,opt,name=files,proto3" json:"files,omitempty"`
+	FilesFree            uint64   `protobuf:"varint,6,opt,name=files_free,json=filesFree,proto3" json:"files_free,omitempty"`
+	NameLength           uint64   `protobuf:"varint,7,opt,name=name_length,json=nameLength,proto3" json:"name_length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatfsResponse) Reset()         { *m = StatfsResponse{} }
+func (m *StatfsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatfsResponse) ProtoMessage()    {}
+func (*StatfsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{44}
+}
+
+func (m *StatfsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatfsResponse.Unmarshal(m, b)
+}
+func (m *StatfsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatfsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatfsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatfsResponse.Merge(m, src)
+}
+func (m *StatfsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatfsResponse.Size(m)
+}
+func (m *StatfsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatfsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatfsResponse proto.InternalMessageInfo
+
+func (m *StatfsResponse) GetBlockSize() uint64 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetBlocks() uint64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetBlocksFree() uint64 {
+	if m != nil {
+		return m.BlocksFree
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetBlocksAvailable() uint64 {
+	if m != nil {
+		return m.BlocksAvailable
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetFiles() uint64 {
+	if m != nil {
+		return m.Files
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetFilesFree() uint64 {
+	if m != nil {
+		return m.FilesFree
+	}
+	return 0
+}
+
+func (m *StatfsResponse) GetNameLength() uint64 {
+	if m != nil {
+		return m.NameLength
+	}
+	return 0
+}
+
+type QuotaUsageRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaUsageRequest) Reset()         { *m = QuotaUsageRequest{} }
+func (m *QuotaUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*QuotaUsageRequest) ProtoMessage()    {}
+func (*QuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{45}
+}
+
+func (m *QuotaUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaUsageRequest.Unmarshal(m, b)
+}
+func (m *QuotaUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *QuotaUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaUsageRequest.Merge(m, src)
+}
+func (m *QuotaUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_QuotaUsageRequest.Size(m)
+}
+func (m *QuotaUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaUsageRequest proto.InternalMessageInfo
+
+type QuotaUsage struct {
+	User                 string   `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Usage                int64    `protobuf:"varint,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaUsage) Reset()         { *m = QuotaUsage{} }
+func (m *QuotaUsage) String() string { return proto.CompactTextString(m) }
+func (*QuotaUsage) ProtoMessage()    {}
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{46}
+}
+
+func (m *QuotaUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaUsage.Unmarshal(m, b)
+}
+func (m *QuotaUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaUsage.Marshal(b, m, deterministic)
+}
+func (m *QuotaUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaUsage.Merge(m, src)
+}
+func (m *QuotaUsage) XXX_Size() int {
+	return xxx_messageInfo_QuotaUsage.Size(m)
+}
+func (m *QuotaUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaUsage proto.InternalMessageInfo
+
+func (m *QuotaUsage) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetUsage() int64 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+type QuotaUsageResponse struct {
+	Quotas               []*QuotaUsage `protobuf:"bytes,1,rep,name=quotas,proto3" json:"quotas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *QuotaUsageResponse) Reset()         { *m = QuotaUsageResponse{} }
+func (m *QuotaUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*QuotaUsageResponse) ProtoMessage()    {}
+func (*QuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{47}
+}
+
+func (m *QuotaUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaUsageResponse.Unmarshal(m, b)
+}
+func (m *QuotaUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *QuotaUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaUsageResponse.Merge(m, src)
+}
+func (m *QuotaUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_QuotaUsageResponse.Size(m)
+}
+func (m *QuotaUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaUsageResponse proto.InternalMessageInfo
+
+func (m *QuotaUsageResponse) GetQuotas() []*QuotaUsage {
+	if m != nil {
+		return m.Quotas
+	}
+	return nil
+}
+
+type ChangeNotification struct {
+	Type                 int32    `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	NewPath              string   `protobuf:"bytes,3,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChangeNotification) Reset()         { *m = ChangeNotification{} }
+func (m *ChangeNotification) String() string { return proto.CompactTextString(m) }
+func (*ChangeNotification) ProtoMessage()    {}
+func (*ChangeNotification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33a9e7139a9492fb, []int{48}
+}
+
+func (m *ChangeNotification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangeNotification.Unmarshal(m, b)
+}
+func (m *ChangeNotification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangeNotification.Marshal(b, m, deterministic)
+}
+func (m *ChangeNotification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangeNotification.Merge(m, src)
+}
+func (m *ChangeNotification) XXX_Size() int {
+	return xxx_messageInfo_ChangeNotification.Size(m)
+}
+func (m *ChangeNotification) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangeNotification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangeNotification proto.InternalMessageInfo
+
+func (m *ChangeNotification) GetType() int32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *ChangeNotification) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ChangeNotification) GetNewPath() string {
+	if m != nil {
+		return m.NewPath
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("filebox.MessageType", MessageType_name, MessageType_value)
+	proto.RegisterType((*Envelope)(nil), "filebox.Envelope")
+	proto.RegisterType((*EmptyResponse)(nil), "filebox.EmptyResponse")
+	proto.RegisterType((*FileInfo)(nil), "filebox.FileInfo")
+	proto.RegisterType((*HelloRequest)(nil), "filebox.HelloRequest")
+	proto.RegisterType((*HelloResponse)(nil), "filebox.HelloResponse")
+	proto.RegisterType((*AuthenticateRequest)(nil), "filebox.AuthenticateRequest")
+	proto.RegisterType((*AuthenticateResponse)(nil), "filebox.AuthenticateResponse")
+	proto.RegisterType((*OpenFileRequest)(nil), "filebox.OpenFileRequest")
+	proto.RegisterType((*OpenFileResponse)(nil), "filebox.OpenFileResponse")
+	proto.RegisterType((*ReadFileRequest)(nil), "filebox.ReadFileRequest")
+	proto.RegisterType((*ReadFileResponse)(nil), "filebox.ReadFileResponse")
+	proto.RegisterType((*ReadDirectoryRequest)(nil), "filebox.ReadDirectoryRequest")
+	proto.RegisterType((*ReadDirectoryResponse)(nil), "filebox.ReadDirectoryResponse")
+	proto.RegisterType((*GetFileAttributesRequest)(nil), "filebox.GetFileAttributesRequest")
+	proto.RegisterType((*GetFileAttributesResponse)(nil), "filebox.GetFileAttributesResponse")
+	proto.RegisterType((*CloseFileRequest)(nil), "filebox.CloseFileRequest")
+	proto.RegisterType((*CreateDirectoryRequest)(nil), "filebox.CreateDirectoryRequest")
+	proto.RegisterType((*CreateFileRequest)(nil), "filebox.CreateFileRequest")
+	proto.RegisterType((*RenameRequest)(nil), "filebox.RenameRequest")
+	proto.RegisterType((*DeleteDirectoryRequest)(nil), "filebox.DeleteDirectoryRequest")
+	proto.RegisterType((*TruncateRequest)(nil), "filebox.TruncateRequest")
+	proto.RegisterType((*DeleteFileRequest)(nil), "filebox.DeleteFileRequest")
+	proto.RegisterType((*WriteFileRequest)(nil), "filebox.WriteFileRequest")
+	proto.RegisterType((*WriteFileResponse)(nil), "filebox.WriteFileResponse")
+	proto.RegisterType((*CreateSymlinkRequest)(nil), "filebox.CreateSymlinkRequest")
+	proto.RegisterType((*ReadSymlinkRequest)(nil), "filebox.ReadSymlinkRequest")
+	proto.RegisterType((*ReadSymlinkResponse)(nil), "filebox.ReadSymlinkResponse")
+	proto.RegisterType((*CreateHardLinkRequest)(nil), "filebox.CreateHardLinkRequest")
+	proto.RegisterType((*GetExtendedAttributeRequest)(nil), "filebox.GetExtendedAttributeRequest")
+	proto.RegisterType((*GetExtendedAttributeResponse)(nil), "filebox.GetExtendedAttributeResponse")
+	proto.RegisterType((*ListExtendedAttributesRequest)(nil), "filebox.ListExtendedAttributesRequest")
+	proto.RegisterType((*ListExtendedAttributesResponse)(nil), "filebox.ListExtendedAttributesResponse")
+	proto.RegisterType((*SetExtendedAttributeRequest)(nil), "filebox.SetExtendedAttributeRequest")
+	proto.RegisterType((*RemoveExtendedAttributeRequest)(nil), "filebox.RemoveExtendedAttributeRequest")
+	proto.RegisterType((*LockRequest)(nil), "filebox.LockRequest")
+	proto.RegisterType((*UnlockRequest)(nil), "filebox.UnlockRequest")
+	proto.RegisterType((*TestLockRequest)(nil), "filebox.TestLockRequest")
+	proto.RegisterType((*TestLockResponse)(nil), "filebox.TestLockResponse")
+	proto.RegisterType((*ChangeModeRequest)(nil), "filebox.ChangeModeRequest")
+	proto.RegisterType((*ChangeOwnerRequest)(nil), "filebox.ChangeOwnerRequest")
+	proto.RegisterType((*ChangeTimesRequest)(nil), "filebox.ChangeTimesRequest")
+	proto.RegisterType((*FsyncFileRequest)(nil), "filebox.FsyncFileRequest")
+	proto.RegisterType((*FsyncDirectoryRequest)(nil), "filebox.FsyncDirectoryRequest")
+	proto.RegisterType((*StatfsRequest)(nil), "filebox.StatfsRequest")
+	proto.RegisterType((*StatfsResponse)(nil), "filebox.StatfsResponse")
+	proto.RegisterType((*QuotaUsageRequest)(nil), "filebox.QuotaUsageRequest")
+	proto.RegisterType((*QuotaUsage)(nil), "filebox.QuotaUsage")
+	proto.RegisterType((*QuotaUsageResponse)(nil), "filebox.QuotaUsageResponse")
+	proto.RegisterType((*ChangeNotification)(nil), "filebox.ChangeNotification")
+}
+
+func init() { proto.RegisterFile("filebox.proto", fileDescriptor_33a9e7139a9492fb) }
+
+var fileDescriptor_33a9e7139a9492fb = []byte{
+	// 2289 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xdb, 0x72, 0xdb, 0xc8,
+	0xd1, 0xfe, 0x79, 0x10, 0x45, 0x36, 0x45, 0x89, 0x1c, 0x51, 0x34, 0x45, 0xd9, 0x12, 0x0d, 0xdb,
+	0x2b, 0xee, 0x7a, 0xd7, 0xf5, 0x97, 0xbd, 0x95, 0xda, 0xd4, 0xa6, 0xd6, 0x4b, 0x93, 0x90, 0xc4,
+	0x2c, 0x0f, 0x36, 0x08, 0xd9, 0xb1, 0x2f, 0xc2, 0x40, 0xc4, 0x50, 0x46, 0x99, 0x04, 0x68, 0x00,
+	0xb2, 0x57, 0x79, 0x82, 0x3c, 0x43, 0x2e, 0xf2, 0x3e, 0xa9, 0xca, 0x65, 0x5e, 0x22, 0xf7, 0x79,
+	0x80, 0xd4, 0x1c, 0x00, 0x0c, 0x40, 0x90, 0x94, 0xed, 0x5c, 0xe4, 0x8a, 0x98, 0x3e, 0x7c, 0xdd,
+	0x33, 0xd3, 0x3d, 0x33, 0xdd, 0x84, 0xc2, 0xc4, 0x98, 0xe2, 0x0b, 0xeb, 0xd7, 0x47, 0x73, 0xdb,
+	0x72, 0x2d, 0xb4, 0xc9, 0x87, 0xd2, 0x3f, 0x12, 0x90, 0x95, 0xcd, 0x0f, 0x78, 0x6a, 0xcd, 0x31,
+	0xba, 0x03, 0x30, 0xc3, 0x8e, 0xa3, 0x5d, 0xe2, 0x91, 0xa1, 0x57, 0x13, 0xf5, 0x44, 0xa3, 0xa0,
+	0xe4, 0x38, 0xa5, 0xa3, 0xa3, 0x23, 0xc8, 0x1b, 0xce, 0xc8, 0xc6, 0xce, 0xdc, 0x32, 0x1d, 0x5c,
+	0x4d, 0xd6, 0x13, 0x8d, 0xac, 0x02, 0x86, 0xa3, 0x70, 0x0a, 0x6a, 0x40, 0xda, 0xbd, 0x9e, 0xe3,
+	0x6a, 0xaa, 0x9e, 0x68, 0x6c, 0x3f, 0x2e, 0x3f, 0xf2, 0x6c, 0xf6, 0x18, 0x84, 0x7a, 0x3d, 0xc7,
+	0x0a, 0x95, 0x40, 0x08, 0xd2, 0xba, 0xe6, 0x6a, 0xd5, 0x74, 0x3d, 0xd1, 0xd8, 0x52, 0xe8, 0x37,
+	0xb1, 0x8e, 0x6d, 0xdb, 0xb2, 0x47, 0x63, 0x4b, 0xc7, 0xd5, 0x0d, 0x66, 0x9d, 0x52, 0x5a, 0x96,
+	0x8e, 0xd1, 0x3d, 0x28, 0x30, 0x36, 0x77, 0xa8, 0x9a, 0xa9, 0x27, 0x1a, 0x39, 0x65, 0x8b, 0x12,
+	0xb9, 0x05, 0x69, 0x07, 0x0a, 0xf2, 0x6c, 0xee, 0x5e, 0x7b, 0x2e, 0x49, 0xff, 0x4e, 0x40, 0xf6,
+	0xc4, 0x98, 0xe2, 0x8e, 0x39, 0xb1, 0x88, 0x55, 0x53, 0x9b, 0x61, 0x3a, 0xb3, 0x9c, 0x42, 0xbf,
+	0x09, 0xcd, 0x31, 0xfe, 0xcc, 0x66, 0x93, 0x52, 0xe8, 0x37, 0xa1, 0xcd, 0x2c, 0x9d, 0xcd, 0xa3,
+	0xa0, 0xd0, 0x6f, 0xb4, 0x0f, 0xd9, 0x99, 0xa5, 0x8f, 0x5c, 0x63, 0x86, 0xa9, 0xd7, 0x29, 0x65,
+	0x73, 0x66, 0xe9, 0xaa, 0x31, 0xc3, 0x68, 0x0f, 0x32, 0x86, 0x33, 0xd2, 0x0d, 0x9b, 0x3a, 0x9d,
+	0x55, 0x36, 0x0c, 0xa7, 0x6d, 0xd8, 0xa8, 0x08, 0xa9, 0x2b, 0x43, 0xa7, 0x6e, 0x16, 0x14, 0xf2,
+	0x49, 0x28, 0x97, 0x86, 0x5e, 0xdd, 0x64, 0x94, 0x4b, 0x83, 0x2e, 0xa9, 0x36, 0x1e, 0x63, 0xc7,
+	0x61, 0xc0, 0x59, 0x0a, 0x0c, 0x8c, 0x44, 0xb1, 0x8f, 0x20, 0x3f, 0x7e, 0xab, 0x99, 0x97, 0x98,
+	0x09, 0xe4, 0x98, 0x00, 0x23, 0x51, 0x81, 0x32, 0x6c, 0x98, 0x53, 0xc3, 0x7c, 0x57, 0x05, 0x8a,
+	0xca, 0x06, 0xd2, 0x0c, 0xb6, 0xce, 0xf0, 0x74, 0x6a, 0x29, 0xf8, 0xfd, 0x15, 0x76, 0x5c, 0x54,
+	0x85, 0xcd, 0x0f, 0xd8, 0x76, 0x0c, 0xcb, 0xe4, 0xdb, 0xea, 0x0d, 0x89, 0x81, 0x99, 0x61, 0x8e,
+	0x3c, 0x6e, 0x92, 0x72, 0x61, 0x66, 0x98, 0x2f, 0xb9, 0x80, 0x04, 0x5b, 0x63, 0x6d, 0xae, 0x5d,
+	0x18, 0x53, 0xc3, 0x35, 0xb0, 0x53, 0x4d, 0xd5, 0x53, 0x64, 0xd9, 0x45, 0x9a, 0xd4, 0x83, 0x02,
+	0x37, 0xc7, 0x23, 0x61, 0xb9, 0xbd, 0x28, 0x5c, 0x32, 0x06, 0xee, 0x14, 0x76, 0x9b, 0x57, 0xee,
+	0x5b, 0x6c, 0xba, 0xc6, 0x58, 0x73, 0xb1, 0x37, 0x89, 0x1a, 0x64, 0xaf, 0x1c, 0x6c, 0x0b, 0x5b,
+	0xe8, 0x8f, 0xc9, 0x32, 0xb8, 0xd6, 0x3b, 0xcc, 0x26, 0x90, 0x53, 0xd8, 0x40, 0x7a, 0x0c, 0xe5,
+	0x30, 0x10, 0x77, 0x6f, 0x05, 0x92, 0xf4, 0x23, 0xec, 0x0c, 0xe6, 0xd8, 0x24, 0x41, 0xe3, 0x19,
+	0x46, 0x90, 0x9e, 0x6b, 0xee, 0x5b, 0x2f, 0x6e, 0xc8, 0x37, 0x31, 0x38, 0x99, 0x6a, 0x97, 0x0e,
+	0x0f, 0x1c, 0x36, 0x90, 0x9e, 0x40, 0x31, 0x50, 0xe6, 0xc6, 0x8e, 0x20, 0x4f, 0x12, 0x61, 0xf4,
+	0x56, 0x33, 0xf5, 0x29, 0xb3, 0x97, 0x56, 0x80, 0x90, 0xce, 0x28, 0x45, 0xfa, 0x23, 0xec, 0x28,
+	0x58, 0xd3, 0x45, 0x8b, 0xeb, 0x74, 0x50, 0x05, 0x32, 0xd6, 0x64, 0xe2, 0x60, 0x97, 0xdb, 0xe7,
+	0x23, 0x3f, 0x9c, 0x53, 0x41, 0x38, 0x4b, 0x32, 0x14, 0x03, 0x7c, 0xee, 0x94, 0x97, 0x80, 0x89,
+	0x70, 0x02, 0x5e, 0x5c, 0xbb, 0x98, 0xa4, 0xb8, 0xa6, 0x73, 0xdc, 0x1c, 0xa5, 0x10, 0x75, 0xe9,
+	0x25, 0x94, 0xc9, 0x6f, 0xdb, 0xb0, 0xf1, 0xd8, 0xb5, 0xec, 0xeb, 0x35, 0xab, 0xa3, 0x4d, 0x5c,
+	0x6c, 0x7b, 0xdb, 0x41, 0x07, 0x84, 0x3a, 0x35, 0x66, 0x86, 0xcb, 0xbd, 0x63, 0x03, 0x49, 0x85,
+	0xbd, 0x08, 0x2e, 0xf7, 0xf1, 0x18, 0x36, 0xc8, 0x8c, 0x9d, 0x6a, 0xa2, 0x9e, 0x6a, 0xe4, 0x1f,
+	0x97, 0xfc, 0xf3, 0xc4, 0x4b, 0x68, 0x85, 0xf1, 0x59, 0xbe, 0xda, 0xde, 0x89, 0x44, 0xbf, 0xa5,
+	0x01, 0x54, 0x4f, 0xb1, 0x4b, 0x24, 0x9b, 0xae, 0x6b, 0x1b, 0x17, 0x57, 0x74, 0x1a, 0xcb, 0x3d,
+	0x8e, 0xac, 0x78, 0x72, 0x61, 0x97, 0x7e, 0x81, 0xfd, 0x18, 0x40, 0xee, 0xea, 0x23, 0xc8, 0x51,
+	0x6d, 0xc3, 0x9c, 0x58, 0x14, 0x36, 0xd6, 0xdd, 0xec, 0x84, 0x7f, 0x91, 0x38, 0x69, 0x4d, 0x2d,
+	0x07, 0x7f, 0xca, 0x9e, 0x4b, 0x3f, 0x43, 0xa5, 0x65, 0x63, 0xcd, 0xc5, 0x37, 0xda, 0x02, 0xef,
+	0x10, 0x4b, 0x06, 0x87, 0x98, 0x74, 0x0c, 0x25, 0x86, 0xb0, 0x26, 0xba, 0x25, 0x19, 0x0a, 0x0a,
+	0x26, 0xe9, 0xe0, 0x09, 0xed, 0x43, 0xd6, 0x9a, 0xea, 0x23, 0x41, 0x70, 0xd3, 0x9a, 0xea, 0xcf,
+	0x89, 0xa1, 0x7d, 0xc8, 0x9a, 0xf8, 0x23, 0x63, 0xb1, 0xed, 0xde, 0x34, 0xf1, 0x47, 0xc2, 0x92,
+	0xbe, 0x85, 0x4a, 0x1b, 0x4f, 0xf1, 0xcd, 0x3c, 0x96, 0xde, 0xc0, 0x8e, 0x6a, 0x5f, 0x99, 0x62,
+	0xca, 0x7f, 0xce, 0x4e, 0xc5, 0xe6, 0xc0, 0x31, 0x94, 0x98, 0x27, 0xeb, 0x66, 0x3e, 0x82, 0xe2,
+	0x2b, 0xdb, 0x70, 0xf1, 0x7f, 0x2b, 0x1b, 0x69, 0x96, 0xa5, 0x82, 0x2c, 0x93, 0x7e, 0x80, 0x92,
+	0x60, 0x80, 0xc7, 0xcf, 0x3d, 0x28, 0xb0, 0xd4, 0xfb, 0x68, 0x1b, 0xae, 0x8b, 0xd9, 0xa9, 0x99,
+	0x52, 0xb6, 0x28, 0xf1, 0x15, 0xa3, 0x49, 0xcf, 0xa0, 0xcc, 0x76, 0x6f, 0x78, 0x3d, 0x23, 0xa7,
+	0xfc, 0xaa, 0x45, 0xaa, 0x40, 0xc6, 0xd5, 0xec, 0x4b, 0xee, 0x51, 0x4e, 0xe1, 0x23, 0xa9, 0x01,
+	0x88, 0x24, 0xdb, 0x7a, 0x04, 0xe9, 0x3b, 0xd8, 0x0d, 0x49, 0x72, 0x4f, 0x03, 0xe0, 0x44, 0x08,
+	0xb8, 0x07, 0x7b, 0xcc, 0xb9, 0x33, 0xcd, 0xd6, 0xbb, 0x02, 0xf6, 0xe7, 0x45, 0x8e, 0x0c, 0x07,
+	0xa7, 0xd8, 0x95, 0x7f, 0x75, 0xb1, 0xa9, 0x63, 0xdd, 0xcf, 0xb8, 0x35, 0x01, 0x4f, 0x0f, 0xf4,
+	0x64, 0x70, 0xbb, 0x4b, 0xdf, 0xc3, 0xed, 0x78, 0x18, 0x3e, 0x9b, 0x32, 0x6c, 0x7c, 0xd0, 0xa6,
+	0x57, 0x98, 0x9f, 0x83, 0x6c, 0x20, 0x3d, 0x81, 0x3b, 0x5d, 0xc3, 0x59, 0x54, 0x5b, 0x75, 0x80,
+	0x48, 0xbf, 0x81, 0xc3, 0x65, 0x4a, 0x81, 0x31, 0xe2, 0x14, 0x3b, 0xcf, 0x72, 0x0a, 0x1b, 0x48,
+	0xef, 0xe1, 0x60, 0xf8, 0xe5, 0x33, 0x0d, 0x66, 0x92, 0x12, 0x66, 0x12, 0xdc, 0x52, 0x69, 0xf1,
+	0x96, 0x3a, 0x83, 0x43, 0x05, 0xcf, 0xac, 0x0f, 0xf8, 0x8b, 0xd7, 0xf7, 0xaf, 0x09, 0xc8, 0x77,
+	0xad, 0xf1, 0xbb, 0x1b, 0x67, 0x0a, 0x82, 0xf4, 0x3b, 0xc3, 0xf4, 0x6e, 0x17, 0xfa, 0x4d, 0x68,
+	0xfe, 0xb3, 0x31, 0xc5, 0x1f, 0x88, 0x65, 0xd8, 0x70, 0x5c, 0xcd, 0x76, 0x3d, 0xc7, 0xe9, 0x80,
+	0x04, 0xdf, 0x14, 0x9b, 0x97, 0xee, 0x5b, 0xfa, 0xd2, 0x4a, 0x29, 0x7c, 0x44, 0xa4, 0xad, 0x8f,
+	0x26, 0xb6, 0xe9, 0x63, 0x2b, 0xad, 0xb0, 0x81, 0xf4, 0x97, 0x04, 0x14, 0xce, 0xcd, 0xe9, 0x97,
+	0xba, 0xe7, 0xbb, 0x92, 0x8a, 0x77, 0x25, 0x1d, 0xef, 0xca, 0x86, 0xe8, 0xca, 0xdf, 0x12, 0xb0,
+	0xa3, 0x62, 0xc7, 0xfd, 0x9f, 0x5d, 0x2b, 0x15, 0x8a, 0x81, 0x7f, 0xc1, 0x1b, 0x81, 0xda, 0x4a,
+	0xc4, 0xd9, 0x4a, 0xc6, 0xdb, 0x4a, 0x89, 0xb6, 0xa4, 0x1f, 0xa1, 0xd4, 0xa2, 0x4f, 0xd5, 0x9e,
+	0xa5, 0xe3, 0x4f, 0xbd, 0xac, 0xba, 0x80, 0x98, 0xf2, 0x80, 0x78, 0xb8, 0x4a, 0x9b, 0xbf, 0xb4,
+	0x99, 0x4b, 0xe2, 0x4b, 0x9b, 0x79, 0x43, 0x3e, 0x25, 0xdd, 0x43, 0x23, 0xaf, 0xe6, 0x75, 0x2f,
+	0x01, 0xf1, 0x4d, 0x9e, 0x5c, 0x78, 0x93, 0x8b, 0xa5, 0x40, 0x2a, 0x54, 0x0a, 0x90, 0x7b, 0xfd,
+	0xc4, 0xb9, 0x36, 0xc7, 0x9f, 0x74, 0xaf, 0x3f, 0x84, 0x3d, 0xaa, 0x74, 0xa3, 0x4b, 0xf2, 0x1e,
+	0x14, 0x86, 0xae, 0xe6, 0x4e, 0x56, 0x9e, 0x45, 0xff, 0x4a, 0xc0, 0xb6, 0x27, 0xc5, 0x37, 0x93,
+	0x3c, 0xee, 0x48, 0x2a, 0x8c, 0xe8, 0xd5, 0xc8, 0x9c, 0xc8, 0x51, 0xca, 0x90, 0x94, 0x3c, 0x15,
+	0xc8, 0xd0, 0x81, 0xc3, 0xef, 0x53, 0x3e, 0x22, 0xce, 0xb3, 0xaf, 0xd1, 0xc4, 0xc6, 0x6c, 0xba,
+	0x69, 0x85, 0x21, 0x39, 0x27, 0x36, 0xc6, 0xe8, 0x6b, 0x28, 0x72, 0x01, 0xed, 0x83, 0x66, 0x4c,
+	0xb5, 0x8b, 0x29, 0xab, 0x8f, 0xd2, 0xca, 0x0e, 0xa3, 0x37, 0x3d, 0x32, 0x3d, 0x8c, 0xe8, 0x7b,
+	0x8e, 0xa7, 0x06, 0x1d, 0x10, 0xc7, 0xe8, 0x07, 0x33, 0xc0, 0x82, 0x92, 0x3e, 0xa6, 0x18, 0xfe,
+	0x11, 0xe4, 0xc9, 0x49, 0x33, 0xe2, 0xf1, 0xb5, 0xc9, 0x1c, 0x20, 0xa4, 0x2e, 0x8b, 0xb1, 0x5d,
+	0x28, 0xbd, 0xb8, 0xb2, 0x5c, 0xed, 0x9c, 0x14, 0x80, 0x7c, 0x51, 0xa4, 0x3f, 0x01, 0x04, 0x44,
+	0xb2, 0x44, 0xe4, 0x79, 0xef, 0x2d, 0x11, 0xf9, 0xf6, 0x97, 0x2d, 0x19, 0x7e, 0xb5, 0x2e, 0xbe,
+	0x4f, 0x09, 0xf5, 0x8a, 0x16, 0x9c, 0x3c, 0xbd, 0xe8, 0x40, 0x6a, 0x02, 0x12, 0xcd, 0xf2, 0x55,
+	0x7e, 0x08, 0x99, 0xf7, 0x84, 0xea, 0xbd, 0x59, 0x77, 0xfd, 0x47, 0xa0, 0x20, 0xcc, 0x45, 0xa4,
+	0x57, 0x5e, 0x48, 0xf6, 0x2d, 0xd7, 0x98, 0x90, 0x0a, 0x85, 0x14, 0x48, 0x62, 0xd6, 0x6d, 0x04,
+	0xe5, 0xf2, 0x82, 0xb3, 0xe2, 0xe5, 0x99, 0x0a, 0x5d, 0x9e, 0xdf, 0xfc, 0x33, 0x07, 0x79, 0xa1,
+	0xe6, 0x46, 0x08, 0xb6, 0xe5, 0xde, 0x73, 0xf5, 0xf5, 0x48, 0x91, 0x87, 0xcf, 0x07, 0xfd, 0xa1,
+	0x5c, 0xfc, 0x3f, 0x54, 0x82, 0xc2, 0x99, 0xdc, 0xed, 0x0e, 0x46, 0x8a, 0xfc, 0xe2, 0x5c, 0x1e,
+	0xaa, 0xc5, 0x04, 0x11, 0xf3, 0x48, 0x5c, 0x2c, 0x89, 0xaa, 0x50, 0x6e, 0x9e, 0xab, 0x67, 0x72,
+	0x5f, 0xed, 0xb4, 0x9a, 0xaa, 0xec, 0x4b, 0xa7, 0xd0, 0x3e, 0xec, 0x45, 0x38, 0x5c, 0x29, 0x8d,
+	0xf6, 0xa0, 0x34, 0x78, 0x2e, 0xf7, 0x47, 0x27, 0x9d, 0x6e, 0xa0, 0xb1, 0x81, 0x2a, 0x80, 0x44,
+	0x32, 0x17, 0xcf, 0x10, 0x71, 0x45, 0x6e, 0xb6, 0xc3, 0xe2, 0x9b, 0x44, 0x5c, 0x24, 0x73, 0xf1,
+	0x2c, 0xaa, 0x41, 0x85, 0xd2, 0xdb, 0x1d, 0x45, 0x6e, 0xa9, 0x03, 0xe5, 0xb5, 0xaf, 0x93, 0x43,
+	0x07, 0x70, 0x6b, 0x81, 0xc7, 0x15, 0x01, 0x1d, 0xc1, 0xc1, 0xa9, 0xac, 0x32, 0xbc, 0xa6, 0xaa,
+	0x2a, 0x9d, 0x67, 0xe7, 0xaa, 0x3c, 0xf4, 0xb5, 0xf3, 0xa8, 0x0e, 0xb7, 0xe3, 0x05, 0x38, 0xc4,
+	0x16, 0xf1, 0xa9, 0xd5, 0x1d, 0x0c, 0xe5, 0xb0, 0xaf, 0x05, 0x74, 0x1b, 0xaa, 0x2d, 0x45, 0x26,
+	0xcb, 0xb0, 0xe8, 0xd5, 0x36, 0xba, 0x05, 0xbb, 0x9c, 0x1b, 0x52, 0xdb, 0x21, 0x2b, 0xae, 0xc8,
+	0xfd, 0x66, 0x2f, 0xa0, 0x15, 0x09, 0x54, 0x5b, 0xee, 0xca, 0xb1, 0x50, 0x25, 0x54, 0x86, 0xa2,
+	0xaa, 0x9c, 0xf7, 0x43, 0x7b, 0x81, 0x88, 0x01, 0xae, 0x13, 0x32, 0xb0, 0x4b, 0xfc, 0x7d, 0xa5,
+	0x74, 0xa2, 0xf4, 0x32, 0x51, 0x08, 0xd1, 0xf9, 0x04, 0xf7, 0xa8, 0xab, 0x67, 0xcd, 0xfe, 0xa9,
+	0x3c, 0xea, 0x0f, 0xd4, 0xce, 0x09, 0xd9, 0xda, 0xce, 0xa0, 0x5f, 0xac, 0x10, 0xa4, 0x93, 0xe1,
+	0xeb, 0x7e, 0x2b, 0x8c, 0x74, 0x8b, 0xac, 0x38, 0xa3, 0x2f, 0x7a, 0x5b, 0x15, 0xd0, 0x7a, 0x83,
+	0x76, 0xa0, 0xb5, 0x4f, 0xc2, 0x8a, 0x33, 0x06, 0xaf, 0xfa, 0xb2, 0xe2, 0x73, 0x6a, 0x02, 0x47,
+	0xed, 0xf4, 0x84, 0xdd, 0x39, 0x20, 0xfb, 0xce, 0x57, 0x71, 0xf8, 0xba, 0xd7, 0xed, 0xf4, 0x7f,
+	0xf1, 0x79, 0xb7, 0x89, 0x16, 0xdd, 0xf7, 0x28, 0xe7, 0x0e, 0x09, 0xd3, 0x08, 0x87, 0xcf, 0xf5,
+	0x50, 0xd8, 0xb4, 0xb3, 0xa6, 0xd2, 0x1e, 0x85, 0x14, 0x8f, 0x90, 0x04, 0x87, 0x24, 0x18, 0xe4,
+	0x3f, 0xa8, 0x72, 0xbf, 0x2d, 0xb7, 0x83, 0x80, 0xf0, 0x65, 0xea, 0xe8, 0x1e, 0x1c, 0x2d, 0x95,
+	0xe1, 0x66, 0xee, 0xa2, 0xfb, 0x50, 0xef, 0x76, 0x86, 0x71, 0x52, 0xc1, 0xec, 0x24, 0xf4, 0x00,
+	0xee, 0xae, 0x90, 0xe2, 0x60, 0xf7, 0x88, 0x57, 0xc3, 0xd5, 0x5e, 0xdd, 0x27, 0x50, 0x8a, 0xdc,
+	0x1b, 0xbc, 0x94, 0x57, 0x89, 0x3d, 0x40, 0x45, 0xd8, 0xea, 0x0e, 0x5a, 0xc1, 0x94, 0xbf, 0x22,
+	0xe1, 0x78, 0xde, 0x0f, 0xd1, 0x8e, 0x49, 0x72, 0xaa, 0xf2, 0x50, 0x1d, 0x85, 0xc8, 0x0d, 0x12,
+	0x0e, 0x22, 0x99, 0xfb, 0xf7, 0x35, 0x81, 0x18, 0xaa, 0x4d, 0xf5, 0x24, 0x98, 0xda, 0x37, 0x68,
+	0x17, 0x76, 0x7c, 0x1a, 0x17, 0x7c, 0x48, 0x42, 0xe3, 0xc5, 0xf9, 0x40, 0x6d, 0x8e, 0xce, 0x87,
+	0xcd, 0xd3, 0xc0, 0xad, 0x6f, 0xc9, 0x56, 0x86, 0x19, 0x5c, 0xe5, 0xbb, 0xc7, 0x7f, 0x2f, 0xc1,
+	0xe6, 0x09, 0x3b, 0x4e, 0xd1, 0x53, 0xc8, 0x7a, 0x8d, 0x16, 0x54, 0xf5, 0x0f, 0xd9, 0x48, 0xe3,
+	0xa6, 0xb6, 0x1f, 0xc3, 0xe1, 0x27, 0xf5, 0x53, 0xc8, 0x7a, 0x4d, 0x11, 0x01, 0x20, 0xd2, 0x87,
+	0xa9, 0xed, 0xc7, 0x70, 0x38, 0x40, 0x1f, 0x0a, 0xa1, 0xb6, 0x05, 0xba, 0x13, 0x92, 0x8d, 0x5e,
+	0xe6, 0xb5, 0xc3, 0x65, 0x6c, 0x8e, 0xf7, 0x06, 0x4a, 0x0b, 0xfd, 0x05, 0x74, 0xd7, 0x57, 0x5a,
+	0xd6, 0xcc, 0xa8, 0x49, 0xab, 0x44, 0x38, 0xf6, 0x4f, 0x90, 0xf3, 0xdb, 0x0d, 0x28, 0x98, 0x53,
+	0xb4, 0x05, 0x51, 0xab, 0xf8, 0xac, 0x50, 0x17, 0x15, 0xfd, 0x1e, 0x76, 0x22, 0x9d, 0x07, 0x74,
+	0x14, 0xa0, 0xc4, 0xf6, 0x24, 0x96, 0x62, 0xfd, 0x0c, 0x10, 0xf4, 0x20, 0x50, 0x2d, 0x02, 0x73,
+	0x13, 0x6f, 0x7e, 0x80, 0x0c, 0x6b, 0x4e, 0xa0, 0x8a, 0xb0, 0xa6, 0x42, 0xb7, 0x62, 0xd5, 0x3c,
+	0x22, 0xfd, 0x08, 0x61, 0x1e, 0xf1, 0x9d, 0x8a, 0xa5, 0x58, 0xbf, 0x83, 0xac, 0xd7, 0xad, 0x10,
+	0x02, 0x28, 0xd2, 0xc0, 0x58, 0xb5, 0x0a, 0x41, 0x3f, 0x42, 0x58, 0x85, 0x85, 0x26, 0xc5, 0x52,
+	0x84, 0x67, 0x90, 0xf3, 0xfb, 0x08, 0xc2, 0x9e, 0x46, 0x9b, 0x17, 0xb5, 0x5a, 0x1c, 0x8b, 0x63,
+	0x9c, 0x40, 0x21, 0xd4, 0x51, 0x10, 0x62, 0x38, 0xae, 0xd3, 0xb0, 0xd4, 0x97, 0x33, 0xc8, 0x0b,
+	0xbd, 0x02, 0x74, 0x10, 0x0a, 0xf5, 0x08, 0xc6, 0xed, 0x78, 0xa6, 0x8f, 0xb4, 0x1d, 0x6e, 0x23,
+	0xa0, 0xc3, 0x88, 0x4b, 0x91, 0xfe, 0xc2, 0x52, 0x9f, 0xc6, 0x50, 0x8e, 0x2b, 0xfd, 0xd1, 0x7d,
+	0x31, 0x5f, 0x96, 0x15, 0xc0, 0xb5, 0x07, 0x6b, 0xa4, 0xb8, 0x11, 0x03, 0x2a, 0xf1, 0x45, 0x3f,
+	0xfa, 0xca, 0x07, 0x58, 0xd9, 0x4a, 0xa8, 0x1d, 0xaf, 0x95, 0xe3, 0xa6, 0x54, 0x28, 0x0f, 0x57,
+	0xcf, 0x67, 0x45, 0x1b, 0x61, 0xe9, 0x2a, 0xbd, 0x81, 0x5b, 0x4b, 0x5a, 0x01, 0xe8, 0x58, 0xd8,
+	0xa8, 0x55, 0xcd, 0x82, 0xa5, 0xd8, 0xdf, 0x43, 0x9a, 0xd4, 0x93, 0x28, 0xf8, 0x23, 0x48, 0x28,
+	0x7f, 0x57, 0x65, 0x37, 0x2b, 0xda, 0x85, 0xec, 0x0e, 0x55, 0xf1, 0x4b, 0x35, 0x9f, 0x42, 0xd6,
+	0xab, 0x61, 0xc5, 0x8c, 0x0c, 0x97, 0xdd, 0xb5, 0xfd, 0x18, 0x8e, 0x70, 0x34, 0xf9, 0xe5, 0xaa,
+	0x78, 0x34, 0x45, 0x6b, 0xd8, 0x15, 0x49, 0x99, 0x17, 0x6a, 0x56, 0x21, 0x11, 0x16, 0x2b, 0xd9,
+	0xf5, 0x18, 0xb4, 0x52, 0x5d, 0xc0, 0x10, 0xeb, 0xd7, 0xa5, 0x18, 0x3f, 0x41, 0xce, 0xaf, 0x43,
+	0x85, 0xc3, 0x21, 0x5a, 0x9b, 0xae, 0x48, 0xe8, 0xed, 0x70, 0x49, 0x2a, 0xa4, 0x61, 0x6c, 0xad,
+	0xba, 0x14, 0xe9, 0xb7, 0x90, 0x61, 0x95, 0xa8, 0xb0, 0x9d, 0xa1, 0x02, 0xb6, 0x76, 0x6b, 0x81,
+	0xce, 0x55, 0xe5, 0x50, 0x11, 0x57, 0x8b, 0x2b, 0xa5, 0x38, 0xc4, 0x41, 0x2c, 0x8f, 0xc3, 0x9c,
+	0xc2, 0xb6, 0x77, 0x79, 0x0f, 0x5d, 0x1b, 0x6b, 0xb3, 0xcf, 0xba, 0xef, 0xff, 0x3f, 0x41, 0x6e,
+	0x0f, 0xff, 0x08, 0xe5, 0x48, 0x9f, 0x77, 0xee, 0x36, 0x12, 0xcf, 0xd2, 0x6f, 0x92, 0xf3, 0x8b,
+	0x8b, 0x0c, 0xfd, 0x37, 0xf6, 0xc9, 0x7f, 0x06, 0x00, 0xe3, 0xe7, 0x9f, 0xad, 0x9e, 0x1d, 0x00,
+	0x00,
+}
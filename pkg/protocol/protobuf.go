@@ -0,0 +1,355 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+// protobufTypes maps the messages in messages.go to their protobuf forms.
+// The fields of both forms have the same names, so they're copied with
+// reflection instead of by hand.
+var protobufTypes = []struct {
+	messageType pb.MessageType
+	native      interface{}
+	wire        proto.Message
+}{
+	{pb.MessageType_EMPTY_RESPONSE, EmptyResponse{}, &pb.EmptyResponse{}},
+	{pb.MessageType_HELLO_REQUEST, HelloRequest{}, &pb.HelloRequest{}},
+	{pb.MessageType_HELLO_RESPONSE, HelloResponse{}, &pb.HelloResponse{}},
+	{pb.MessageType_AUTHENTICATE_REQUEST, AuthenticateRequest{}, &pb.AuthenticateRequest{}},
+	{pb.MessageType_AUTHENTICATE_RESPONSE, AuthenticateResponse{}, &pb.AuthenticateResponse{}},
+	{pb.MessageType_OPEN_FILE_REQUEST, OpenFileRequest{}, &pb.OpenFileRequest{}},
+	{pb.MessageType_OPEN_FILE_RESPONSE, OpenFileResponse{}, &pb.OpenFileResponse{}},
+	{pb.MessageType_READ_FILE_REQUEST, ReadFileRequest{}, &pb.ReadFileRequest{}},
+	{pb.MessageType_READ_FILE_RESPONSE, ReadFileResponse{}, &pb.ReadFileResponse{}},
+	{pb.MessageType_READ_DIRECTORY_REQUEST, ReadDirectoryRequest{}, &pb.ReadDirectoryRequest{}},
+	{pb.MessageType_READ_DIRECTORY_RESPONSE, ReadDirectoryResponse{}, &pb.ReadDirectoryResponse{}},
+	{pb.MessageType_GET_FILE_ATTRIBUTES_REQUEST, GetFileAttributesRequest{}, &pb.GetFileAttributesRequest{}},
+	{pb.MessageType_GET_FILE_ATTRIBUTES_RESPONSE, GetFileAttributesResponse{}, &pb.GetFileAttributesResponse{}},
+	{pb.MessageType_CLOSE_FILE_REQUEST, CloseFileRequest{}, &pb.CloseFileRequest{}},
+	{pb.MessageType_CREATE_DIRECTORY_REQUEST, CreateDirectoryRequest{}, &pb.CreateDirectoryRequest{}},
+	{pb.MessageType_CREATE_FILE_REQUEST, CreateFileRequest{}, &pb.CreateFileRequest{}},
+	{pb.MessageType_RENAME_REQUEST, RenameRequest{}, &pb.RenameRequest{}},
+	{pb.MessageType_DELETE_DIRECTORY_REQUEST, DeleteDirectoryRequest{}, &pb.DeleteDirectoryRequest{}},
+	{pb.MessageType_TRUNCATE_REQUEST, TruncateRequest{}, &pb.TruncateRequest{}},
+	{pb.MessageType_DELETE_FILE_REQUEST, DeleteFileRequest{}, &pb.DeleteFileRequest{}},
+	{pb.MessageType_WRITE_FILE_REQUEST, WriteFileRequest{}, &pb.WriteFileRequest{}},
+	{pb.MessageType_WRITE_FILE_RESPONSE, WriteFileResponse{}, &pb.WriteFileResponse{}},
+	{pb.MessageType_CHANGE_NOTIFICATION, ChangeNotification{}, &pb.ChangeNotification{}},
+	{pb.MessageType_FSYNC_FILE_REQUEST, FsyncFileRequest{}, &pb.FsyncFileRequest{}},
+	{pb.MessageType_FSYNC_DIRECTORY_REQUEST, FsyncDirectoryRequest{}, &pb.FsyncDirectoryRequest{}},
+	{pb.MessageType_CREATE_SYMLINK_REQUEST, CreateSymlinkRequest{}, &pb.CreateSymlinkRequest{}},
+	{pb.MessageType_READ_SYMLINK_REQUEST, ReadSymlinkRequest{}, &pb.ReadSymlinkRequest{}},
+	{pb.MessageType_READ_SYMLINK_RESPONSE, ReadSymlinkResponse{}, &pb.ReadSymlinkResponse{}},
+	{pb.MessageType_CREATE_HARD_LINK_REQUEST, CreateHardLinkRequest{}, &pb.CreateHardLinkRequest{}},
+	{pb.MessageType_GET_EXTENDED_ATTRIBUTE_REQUEST, GetExtendedAttributeRequest{}, &pb.GetExtendedAttributeRequest{}},
+	{pb.MessageType_GET_EXTENDED_ATTRIBUTE_RESPONSE, GetExtendedAttributeResponse{}, &pb.GetExtendedAttributeResponse{}},
+	{pb.MessageType_LIST_EXTENDED_ATTRIBUTES_REQUEST, ListExtendedAttributesRequest{}, &pb.ListExtendedAttributesRequest{}},
+	{pb.MessageType_LIST_EXTENDED_ATTRIBUTES_RESPONSE, ListExtendedAttributesResponse{}, &pb.ListExtendedAttributesResponse{}},
+	{pb.MessageType_SET_EXTENDED_ATTRIBUTE_REQUEST, SetExtendedAttributeRequest{}, &pb.SetExtendedAttributeRequest{}},
+	{pb.MessageType_REMOVE_EXTENDED_ATTRIBUTE_REQUEST, RemoveExtendedAttributeRequest{}, &pb.RemoveExtendedAttributeRequest{}},
+	{pb.MessageType_LOCK_REQUEST, LockRequest{}, &pb.LockRequest{}},
+	{pb.MessageType_UNLOCK_REQUEST, UnlockRequest{}, &pb.UnlockRequest{}},
+	{pb.MessageType_TEST_LOCK_REQUEST, TestLockRequest{}, &pb.TestLockRequest{}},
+	{pb.MessageType_TEST_LOCK_RESPONSE, TestLockResponse{}, &pb.TestLockResponse{}},
+	{pb.MessageType_CHANGE_MODE_REQUEST, ChangeModeRequest{}, &pb.ChangeModeRequest{}},
+	{pb.MessageType_CHANGE_OWNER_REQUEST, ChangeOwnerRequest{}, &pb.ChangeOwnerRequest{}},
+	{pb.MessageType_CHANGE_TIMES_REQUEST, ChangeTimesRequest{}, &pb.ChangeTimesRequest{}},
+	{pb.MessageType_STATFS_REQUEST, StatfsRequest{}, &pb.StatfsRequest{}},
+	{pb.MessageType_STATFS_RESPONSE, StatfsResponse{}, &pb.StatfsResponse{}},
+	{pb.MessageType_QUOTA_USAGE_REQUEST, QuotaUsageRequest{}, &pb.QuotaUsageRequest{}},
+	{pb.MessageType_QUOTA_USAGE_RESPONSE, QuotaUsageResponse{}, &pb.QuotaUsageResponse{}},
+}
+
+type protobufEncoder struct {
+	writer io.Writer
+}
+
+// Encode writes a message as a single frame: a 4-byte big-endian length,
+// followed by the encoded pb.Envelope.
+func (encoder *protobufEncoder) Encode(message *Message) error {
+	envelope, err := ToEnvelope(message)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, len(data))
+	}
+
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	_, err = encoder.writer.Write(frame)
+	return err
+}
+
+type protobufDecoder struct {
+	reader io.Reader
+}
+
+// Decode reads a single frame. It returns io.EOF if the connection was closed
+// between frames.
+func (decoder *protobufDecoder) Decode(message *Message) error {
+	var header [4]byte
+	if _, err := io.ReadFull(decoder.reader, header[:]); err != nil {
+		return err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if size > MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(decoder.reader, data); err == io.EOF {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+
+	envelope := &pb.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return err
+	}
+
+	return FromEnvelope(envelope, message)
+}
+
+// ToEnvelope converts a message to its protobuf form.
+func ToEnvelope(message *Message) (*pb.Envelope, error) {
+	envelope := &pb.Envelope{
+		MessageId:    message.MessageID,
+		IsResponse:   message.IsResponse,
+		ErrorCode:    uint32(message.ErrorCode),
+		ErrorMessage: message.ErrorMessage,
+	}
+
+	if message.Data == nil {
+		return envelope, nil
+	}
+
+	wire, messageType, err := ToProtobuf(message.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Type = messageType
+	if envelope.Data, err = proto.Marshal(wire); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+// RequestNames returns the type names of all the requests, such as
+// "ReadFileRequest".
+func RequestNames() []string {
+	var names []string
+	for _, entry := range protobufTypes {
+		if name := reflect.TypeOf(entry.native).Name(); strings.HasSuffix(name, "Request") {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// FromEnvelope converts the protobuf form of a message back.
+func FromEnvelope(envelope *pb.Envelope, message *Message) error {
+	*message = Message{
+		MessageID:    envelope.MessageId,
+		IsResponse:   envelope.IsResponse,
+		ErrorCode:    ErrorCode(envelope.ErrorCode),
+		ErrorMessage: envelope.ErrorMessage,
+	}
+
+	for _, entry := range protobufTypes {
+		if entry.messageType != envelope.Type {
+			continue
+		}
+
+		wire := reflect.New(reflect.TypeOf(entry.wire).Elem()).Interface().(proto.Message)
+		if err := proto.Unmarshal(envelope.Data, wire); err != nil {
+			return err
+		}
+
+		var err error
+		message.Data, err = FromProtobuf(wire)
+		return err
+	}
+
+	return fmt.Errorf("unknown message type %d", envelope.Type)
+}
+
+// ToProtobuf converts a request, a response or a notification to its protobuf
+// form. data can be a value or a pointer.
+func ToProtobuf(data interface{}) (proto.Message, pb.MessageType, error) {
+	value := reflect.Indirect(reflect.ValueOf(data))
+
+	for _, entry := range protobufTypes {
+		if reflect.TypeOf(entry.native) != value.Type() {
+			continue
+		}
+
+		wire := reflect.New(reflect.TypeOf(entry.wire).Elem())
+		if err := convertValue(wire.Elem(), value); err != nil {
+			return nil, 0, err
+		}
+
+		return wire.Interface().(proto.Message), entry.messageType, nil
+	}
+
+	return nil, 0, fmt.Errorf("%s has no protobuf form", value.Type())
+}
+
+// FromProtobuf converts the protobuf form of a message back. The result is a
+// value, such as ReadFileRequest, like the ones that gob decodes.
+func FromProtobuf(wire proto.Message) (interface{}, error) {
+	value := reflect.Indirect(reflect.ValueOf(wire))
+
+	for _, entry := range protobufTypes {
+		if reflect.TypeOf(entry.wire).Elem() != value.Type() {
+			continue
+		}
+
+		native := reflect.New(reflect.TypeOf(entry.native)).Elem()
+		if err := convertValue(native, value); err != nil {
+			return nil, err
+		}
+
+		return native.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("%s has no native form", value.Type())
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// convertValue copies src to dst, which have matching types: structs with
+// the same field names, numbers of any size, and slices of those. Nested
+// protobuf messages are pointers, and times are sent as Unix nanoseconds.
+func convertValue(dst reflect.Value, src reflect.Value) error {
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			return nil
+		}
+
+		src = src.Elem()
+	}
+
+	switch {
+	case src.Type() == timeType && dst.Kind() == reflect.Int64:
+		if t := src.Interface().(time.Time); !t.IsZero() {
+			dst.SetInt(t.UnixNano())
+		}
+		return nil
+
+	case dst.Type() == timeType && src.Kind() == reflect.Int64:
+		if src.Int() != 0 {
+			dst.Set(reflect.ValueOf(time.Unix(0, src.Int())))
+		}
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(dst.Type().Elem())
+		if err := convertValue(value.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+
+	case reflect.Struct:
+		if src.Kind() != reflect.Struct {
+			break
+		}
+
+		for i := 0; i < dst.NumField(); i++ {
+			// The generated messages keep unknown fields and their cached
+			// size in fields of their own.
+			field := dst.Type().Field(i)
+			if strings.HasPrefix(field.Name, "XXX_") {
+				continue
+			}
+
+			srcField := src.FieldByName(field.Name)
+			if !srcField.IsValid() {
+				return fmt.Errorf("%s has no field %s", src.Type(), field.Name)
+			}
+
+			if err := convertValue(dst.Field(i), srcField); err != nil {
+				return fmt.Errorf("%s.%s: %w", dst.Type(), field.Name, err)
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			break
+		} else if src.IsNil() {
+			return nil
+		}
+
+		slice := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		if dst.Type().Elem().Kind() == reflect.Uint8 && src.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(slice, src)
+		} else {
+			for i := 0; i < src.Len(); i++ {
+				if err := convertValue(slice.Index(i), src.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+		dst.Set(slice)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch src.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetInt(src.Int())
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetInt(int64(src.Uint()))
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch src.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetUint(uint64(src.Int()))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetUint(src.Uint())
+			return nil
+		}
+
+	case reflect.String:
+		if src.Kind() == reflect.String {
+			dst.SetString(src.String())
+			return nil
+		}
+
+	case reflect.Bool:
+		if src.Kind() == reflect.Bool {
+			dst.SetBool(src.Bool())
+			return nil
+		}
+	}
+
+	return fmt.Errorf("can't convert %s to %s", src.Type(), dst.Type())
+}
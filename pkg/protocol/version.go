@@ -0,0 +1,89 @@
+package protocol
+
+import "fmt"
+
+// Protocol versions that this package speaks. Version is incremented whenever
+// a message changes in a way that older peers can't understand. Peers agree
+// on the highest version that both of them support.
+const (
+	Version    uint32 = 1
+	MinVersion uint32 = 1
+
+	// LegacyVersion is the version of clients that don't send a HelloRequest.
+	// They start the connection with an AuthenticateRequest instead.
+	LegacyVersion uint32 = 0
+)
+
+// Capability is an optional feature of the protocol. A capability is used on
+// a connection only if both peers announce it in the hello exchange.
+type Capability string
+
+const (
+	// CapabilityNotifications means the client accepts ChangeNotification messages.
+	CapabilityNotifications Capability = "notifications"
+
+	// CapabilitySymlinks means the client handles symbolic links itself, so
+	// GetFileAttributes returns the attributes of the link rather than of
+	// the file that it points to.
+	CapabilitySymlinks Capability = "symlinks"
+
+	// CapabilityLocks means the peer supports LockRequest, UnlockRequest and
+	// TestLockRequest. The server only announces it if it keeps locks.
+	CapabilityLocks Capability = "locks"
+)
+
+// Capabilities is a set of capabilities.
+type Capabilities []Capability
+
+// Has returns true if the set contains capability.
+func (capabilities Capabilities) Has(capability Capability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HelloRequest is the first message that a client sends. It announces the
+// range of protocol versions and the capabilities that the client supports.
+type HelloRequest struct {
+	Version      uint32 // highest supported version
+	MinVersion   uint32 // lowest supported version
+	Capabilities Capabilities
+}
+
+// HelloResponse contains the protocol version and the capabilities that were
+// agreed on. The rest of the connection uses them.
+type HelloResponse struct {
+	Version      uint32
+	Capabilities Capabilities
+}
+
+// Negotiate picks the highest protocol version that both peers support, and
+// the capabilities that both of them announced. It fails with
+// ErrorVersionMismatch if the version ranges don't overlap.
+func Negotiate(request HelloRequest, supported Capabilities) (HelloResponse, error) {
+	version := request.Version
+	if version > Version {
+		version = Version
+	}
+
+	if version < MinVersion || version < request.MinVersion {
+		return HelloResponse{}, &Error{
+			Code: ErrorVersionMismatch,
+			Message: fmt.Sprintf("no common protocol version (client supports %d-%d, server supports %d-%d)",
+				request.MinVersion, request.Version, MinVersion, Version),
+		}
+	}
+
+	response := HelloResponse{Version: version}
+	for _, capability := range request.Capabilities {
+		if supported.Has(capability) {
+			response.Capabilities = append(response.Capabilities, capability)
+		}
+	}
+
+	return response, nil
+}
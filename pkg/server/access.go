@@ -0,0 +1,193 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// openFileActions returns the actions that opening a file with the given
+// flags performs.
+func openFileActions(flags int) []Action {
+	var actions []Action
+
+	switch flags & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		actions = append(actions, ActionRead)
+	case os.O_WRONLY:
+		actions = append(actions, ActionWrite)
+	case os.O_RDWR:
+		actions = append(actions, ActionRead, ActionWrite)
+	}
+
+	if flags&(os.O_TRUNC|os.O_APPEND) != 0 {
+		actions = append(actions, ActionWrite)
+	}
+
+	if flags&os.O_CREATE != 0 {
+		actions = append(actions, ActionCreate)
+	}
+
+	return actions
+}
+
+// accessCheck is an action that a request performs on a path. Requests that
+// refer to an open file handle have no resolve function.
+type accessCheck struct {
+	resolve func(string) (string, error)
+	path    string
+	actions []Action
+}
+
+// accessChecks returns the actions that a request performs.
+func (handler *FileboxMessageHandler) accessChecks(request interface{}) []accessCheck {
+	var checks []accessCheck
+
+	switch request := request.(type) {
+	case protocol.OpenFileRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, openFileActions(request.Flags)})
+
+	case protocol.ReadDirectoryRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+
+	case protocol.GetFileAttributesRequest:
+		if !handler.isFileHandle(request.FileHandle) {
+			checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+		}
+
+	case protocol.CreateDirectoryRequest:
+		checks = append(checks, accessCheck{handler.resolveEntry, request.Path, []Action{ActionCreate}})
+
+	case protocol.CreateFileRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionCreate}})
+
+	case protocol.RenameRequest:
+		// A rename onto an existing file deletes that file.
+		newPathActions := []Action{ActionRename}
+		if newPath, err := handler.resolveEntry(request.NewPath); err == nil {
+			if _, err := os.Lstat(newPath); err == nil {
+				newPathActions = append(newPathActions, ActionDelete)
+			}
+		}
+
+		checks = append(checks,
+			accessCheck{handler.resolveEntry, request.OldPath, []Action{ActionRename}},
+			accessCheck{handler.resolveEntry, request.NewPath, newPathActions})
+
+	case protocol.DeleteDirectoryRequest:
+		checks = append(checks, accessCheck{handler.resolveEntry, request.Path, []Action{ActionDelete}})
+
+	case protocol.DeleteFileRequest:
+		checks = append(checks, accessCheck{handler.resolveEntry, request.Path, []Action{ActionDelete}})
+
+	case protocol.TruncateRequest:
+		if !handler.isFileHandle(request.FileHandle) {
+			checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+		} else {
+			checks = append(checks, accessCheck{nil, request.Path, []Action{ActionWrite}})
+		}
+
+	case protocol.WriteFileRequest:
+		checks = append(checks, accessCheck{nil, "", []Action{ActionWrite}})
+
+	case protocol.CreateSymlinkRequest:
+		checks = append(checks, accessCheck{handler.resolveEntry, request.Path, []Action{ActionCreate}})
+
+	case protocol.ReadSymlinkRequest:
+		checks = append(checks, accessCheck{handler.resolveEntry, request.Path, []Action{ActionRead}})
+
+	case protocol.CreateHardLinkRequest:
+		// The new name gives the same access to the file as the old one, so
+		// a link must not make a file writable under another path.
+		checks = append(checks,
+			accessCheck{handler.resolveEntry, request.OldPath, []Action{ActionRead, ActionWrite}},
+			accessCheck{handler.resolveEntry, request.NewPath, []Action{ActionCreate}})
+
+	case protocol.GetExtendedAttributeRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+
+	case protocol.ListExtendedAttributesRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+
+	case protocol.SetExtendedAttributeRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+
+	case protocol.RemoveExtendedAttributeRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+
+	case protocol.ChangeModeRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+
+	case protocol.ChangeOwnerRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+
+	case protocol.ChangeTimesRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionWrite}})
+
+	case protocol.FsyncDirectoryRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+
+	case protocol.StatfsRequest:
+		checks = append(checks, accessCheck{handler.resolvePath, request.Path, []Action{ActionRead}})
+	}
+
+	return checks
+}
+
+// authorize checks whether a request is allowed. In read-only mode, only
+// requests that read are allowed. Otherwise, the request is checked against
+// the handler's access policy. Paths are resolved first, so a symbolic link
+// can't be used to get around a rule.
+func (handler *FileboxMessageHandler) authorize(request interface{}) error {
+	if !handler.ReadOnly && handler.Policy == nil {
+		return nil
+	}
+
+	checks := handler.accessChecks(request)
+
+	if handler.ReadOnly {
+		for _, check := range checks {
+			for _, action := range check.actions {
+				if action != ActionRead {
+					return syscall.EROFS
+				}
+			}
+		}
+	}
+
+	if handler.Policy == nil {
+		return nil
+	}
+
+	root, err := handler.root()
+	if err != nil {
+		return err
+	}
+
+	for _, check := range checks {
+		if check.resolve == nil {
+			// Writes through file handles were checked when the file was opened.
+			continue
+		}
+
+		resolved, err := check.resolve(check.path)
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(root, resolved)
+		if err != nil {
+			return err
+		}
+
+		for _, action := range check.actions {
+			if err := handler.Policy.Check(handler.Username, action, relative); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// ErrAuthenticationFailed is returned when a client presents wrong credentials.
+var ErrAuthenticationFailed = fmt.Errorf("authentication failed: %w", os.ErrPermission)
+
+// AnonymousUser is the identity of clients that don't have a token of their
+// own: clients that present the shared secret, and every client of a server
+// without authentication. The username that such clients send is ignored, so
+// they can't pose as another user.
+const AnonymousUser = "anonymous"
+
+// Authenticator checks the credentials that clients present when they connect.
+// A client is accepted if its token matches the token of its username in the
+// tokens file, or the shared secret.
+type Authenticator struct {
+	Secret string
+	Tokens map[string]string // username -> token
+}
+
+// LoadTokens reads a tokens file into the authenticator. Every line in the
+// file has the form "username:token". Empty lines and lines that start with
+// '#' are ignored.
+func (authenticator *Authenticator) LoadTokens(tokensFile string) error {
+	file, err := os.Open(tokensFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if authenticator.Tokens == nil {
+		authenticator.Tokens = make(map[string]string)
+	}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("%s:%d: expected username:token", tokensFile, lineNumber)
+		}
+
+		if parts[0] == AnonymousUser {
+			return fmt.Errorf("%s:%d: %q is reserved for clients without a token", tokensFile, lineNumber, AnonymousUser)
+		}
+
+		authenticator.Tokens[parts[0]] = parts[1]
+	}
+
+	return scanner.Err()
+}
+
+// Authenticate checks the credentials, and returns the identity that they
+// prove: the username of a per-user token, or AnonymousUser for the shared
+// secret. A nil authenticator accepts everyone as AnonymousUser.
+func (authenticator *Authenticator) Authenticate(request protocol.AuthenticateRequest) (string, error) {
+	if authenticator == nil {
+		return AnonymousUser, nil
+	}
+
+	if token, ok := authenticator.Tokens[request.Username]; ok && secureCompare(request.Token, token) {
+		return request.Username, nil
+	}
+
+	if authenticator.Secret != "" && secureCompare(request.Token, authenticator.Secret) {
+		return AnonymousUser, nil
+	}
+
+	return "", ErrAuthenticationFailed
+}
+
+func secureCompare(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// authenticate handles the message that follows the hello exchange, which
+// must be an AuthenticateRequest. It returns the username that the client's
+// credentials prove. If authenticator is nil, every client is accepted as
+// AnonymousUser.
+func authenticate(authenticator *Authenticator, encoder protocol.Encoder, message *protocol.Message) (string, error) {
+	request, ok := message.Data.(protocol.AuthenticateRequest)
+	if !ok {
+		return "", errors.New("expected an AuthenticateRequest message")
+	}
+
+	username, err := authenticator.Authenticate(request)
+
+	response := &protocol.Message{
+		MessageID:  message.MessageID,
+		IsResponse: true,
+		Data:       &protocol.AuthenticateResponse{Username: username},
+	}
+
+	if err != nil {
+		response.Data = &protocol.EmptyResponse{}
+		response.ErrorCode = protocol.ErrorCodeOf(err)
+		response.ErrorMessage = err.Error()
+	}
+
+	if encodeErr := encoder.Encode(response); encodeErr != nil {
+		return "", encodeErr
+	}
+
+	if err != nil {
+		return request.Username, err
+	}
+
+	return username, nil
+}
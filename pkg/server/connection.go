@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"net"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// clientConnection serializes the messages that are sent to a client. Every
+// request is handled in its own goroutine, so instead of writing to the
+// connection directly, they put their responses in a queue, and a single
+// writer goroutine encodes them.
+type clientConnection struct {
+	connection net.Conn
+	encoder    protocol.Encoder
+	queue      chan *protocol.Message
+	closed     chan struct{}
+}
+
+// sendQueueSize is the number of messages that can wait for the writer
+// goroutine before senders block.
+const sendQueueSize = 128
+
+func newClientConnection(connection net.Conn, encoder protocol.Encoder) *clientConnection {
+	return &clientConnection{
+		connection: connection,
+		encoder:    encoder,
+		queue:      make(chan *protocol.Message, sendQueueSize),
+		closed:     make(chan struct{}),
+	}
+}
+
+// send queues a message, and blocks while the queue is full. Messages that
+// are sent after the connection is closed are dropped.
+func (client *clientConnection) send(message *protocol.Message) {
+	select {
+	case client.queue <- message:
+	case <-client.closed:
+	}
+}
+
+// trySend queues a message without blocking. It returns false if the queue
+// is full or the connection is closed.
+func (client *clientConnection) trySend(message *protocol.Message) bool {
+	select {
+	case <-client.closed:
+		return false
+	default:
+	}
+
+	select {
+	case client.queue <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeMessages encodes the queued messages until the connection is closed.
+func (client *clientConnection) writeMessages() {
+	for {
+		select {
+		case message := <-client.queue:
+			err := client.encoder.Encode(message)
+			if errors.Is(err, protocol.ErrMessageTooLarge) {
+				// The connection is still usable, so fail only the request.
+				// Closing the connection would make the client send the
+				// request again after it reconnects.
+				log.WithField("MessageID", message.MessageID).WithError(err).Error("Message is too large to send")
+				if !message.IsResponse {
+					continue
+				}
+
+				err = client.encoder.Encode(&protocol.Message{
+					MessageID:    message.MessageID,
+					IsResponse:   true,
+					Data:         &protocol.EmptyResponse{},
+					ErrorCode:    protocol.ErrorUnknown,
+					ErrorMessage: "response is too large",
+				})
+			}
+
+			if err != nil {
+				log.WithError(err).Error("encoder.Encode failed")
+
+				// Make the reader fail too, so the connection is cleaned up.
+				client.connection.Close()
+			}
+
+		case <-client.closed:
+			return
+		}
+	}
+}
+
+// close stops the writer goroutine. Messages that are still queued are dropped.
+func (client *clientConnection) close() {
+	close(client.closed)
+}
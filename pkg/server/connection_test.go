@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestSendTooLarge(t *testing.T) {
+	log.SetLevel(log.FatalLevel)
+	protocol.Init()
+
+	for _, encoding := range []protocol.Encoding{protocol.EncodingGob, protocol.EncodingProtobuf} {
+		t.Run(string(encoding), func(t *testing.T) {
+			connection, peer := net.Pipe()
+			defer peer.Close()
+
+			encoder, err := protocol.NewEncoder(encoding, connection)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := newClientConnection(connection, encoder)
+			go client.writeMessages()
+			defer client.close()
+
+			client.send(&protocol.Message{
+				MessageID:  1,
+				IsResponse: true,
+				Data:       protocol.ReadFileResponse{Data: make([]byte, protocol.MaxMessageSize+1)},
+			})
+			client.send(&protocol.Message{MessageID: 2, IsResponse: true, Data: protocol.EmptyResponse{}})
+
+			decoder, err := protocol.NewDecoder(encoding, peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The large response is replaced with an error, and the
+			// connection stays open for the next one.
+			var message protocol.Message
+			if err := decoder.Decode(&message); err != nil {
+				t.Fatal(err)
+			}
+			if message.MessageID != 1 || message.ErrorCode == protocol.ErrorNone {
+				t.Errorf("got %+v, want an error response to message 1", message)
+			}
+
+			message = protocol.Message{}
+			if err := decoder.Decode(&message); err != nil {
+				t.Fatal(err)
+			}
+			if message.MessageID != 2 || message.ErrorCode != protocol.ErrorNone {
+				t.Errorf("got %+v, want the response to message 2", message)
+			}
+		})
+	}
+}
@@ -1,3 +1,4 @@
+//go:build !windows
 // +build !windows
 
 package server
@@ -6,4 +7,15 @@ import "os"
 
 func openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
 	return os.OpenFile(name, flag, perm)
-}
\ No newline at end of file
+}
+
+// syncDirectory writes the entries of a directory to the disk.
+func syncDirectory(name string) error {
+	directory, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer directory.Close()
+
+	return directory.Sync()
+}
@@ -7,8 +7,6 @@ import (
 	"os"
 	"syscall"
 	"unsafe"
-
-	"github.com/pkg/errors"
 )
 
 func openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
@@ -17,11 +15,22 @@ func openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
 	}
 	h, err := syscallOpen(fixLongPath(name), flag|syscall.O_CLOEXEC, syscallMode(perm))
 	if err != nil {
-		return nil, errors.Wrap(err, "error opening file")
+		// Keep the system error reachable, so it can be reported to the client.
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
 	}
 	return os.NewFile(uintptr(h), name), nil
 }
 
+// syncDirectory is a no-op on Windows, which can't flush a directory handle.
+// NTFS journals the changes to directory entries on its own.
+func syncDirectory(name string) error {
+	if _, err := os.Stat(name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // syscallOpen is copied from syscall.Open but is modified to
 // always open a file with FILE_SHARE_DELETE
 func syscallOpen(path string, mode int, perm uint32) (fd syscall.Handle, err error) {
@@ -0,0 +1,396 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/alongubkin/filebox/pkg/protocol/pb"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC service serves the same requests as the Filebox protocol, with the
+// messages of pkg/protocol/pb. It's defined by the Filebox service in
+// filebox.proto. Like on a Filebox connection, file handles belong to the
+// gRPC connection that opened them, and are closed when it's closed.
+//
+// Clients authenticate by sending their credentials in the "username" and
+// "token" metadata of every call. The protocol error code of a failed call is
+// sent in the "filebox-error-code" trailer.
+
+const (
+	grpcServiceName = "filebox.Filebox"
+
+	// grpcChunkSize is the size of the chunks that ReadFileStream sends.
+	grpcChunkSize = 64 * 1024
+)
+
+// grpcMethods are the unary methods of the service, along with their request types.
+var grpcMethods = []struct {
+	name    string
+	request proto.Message
+}{
+	{"OpenFile", &pb.OpenFileRequest{}},
+	{"ReadFile", &pb.ReadFileRequest{}},
+	{"ReadDirectory", &pb.ReadDirectoryRequest{}},
+	{"GetFileAttributes", &pb.GetFileAttributesRequest{}},
+	{"CloseFile", &pb.CloseFileRequest{}},
+	{"CreateDirectory", &pb.CreateDirectoryRequest{}},
+	{"CreateFile", &pb.CreateFileRequest{}},
+	{"Rename", &pb.RenameRequest{}},
+	{"DeleteDirectory", &pb.DeleteDirectoryRequest{}},
+	{"Truncate", &pb.TruncateRequest{}},
+	{"DeleteFile", &pb.DeleteFileRequest{}},
+	{"WriteFile", &pb.WriteFileRequest{}},
+	{"CreateSymlink", &pb.CreateSymlinkRequest{}},
+	{"ReadSymlink", &pb.ReadSymlinkRequest{}},
+	{"CreateHardLink", &pb.CreateHardLinkRequest{}},
+	{"GetExtendedAttribute", &pb.GetExtendedAttributeRequest{}},
+	{"ListExtendedAttributes", &pb.ListExtendedAttributesRequest{}},
+	{"SetExtendedAttribute", &pb.SetExtendedAttributeRequest{}},
+	{"RemoveExtendedAttribute", &pb.RemoveExtendedAttributeRequest{}},
+	{"Lock", &pb.LockRequest{}},
+	{"Unlock", &pb.UnlockRequest{}},
+	{"TestLock", &pb.TestLockRequest{}},
+	{"ChangeMode", &pb.ChangeModeRequest{}},
+	{"ChangeOwner", &pb.ChangeOwnerRequest{}},
+	{"ChangeTimes", &pb.ChangeTimesRequest{}},
+	{"FsyncFile", &pb.FsyncFileRequest{}},
+	{"FsyncDirectory", &pb.FsyncDirectoryRequest{}},
+	{"Statfs", &pb.StatfsRequest{}},
+	{"QuotaUsage", &pb.QuotaUsageRequest{}},
+}
+
+// grpcErrorCodes maps protocol error codes to gRPC status codes.
+var grpcErrorCodes = map[protocol.ErrorCode]codes.Code{
+	protocol.ErrorNotFound:        codes.NotFound,
+	protocol.ErrorPermission:      codes.PermissionDenied,
+	protocol.ErrorExists:          codes.AlreadyExists,
+	protocol.ErrorNotEmpty:        codes.FailedPrecondition,
+	protocol.ErrorIsDirectory:     codes.FailedPrecondition,
+	protocol.ErrorNotDirectory:    codes.FailedPrecondition,
+	protocol.ErrorInvalid:         codes.InvalidArgument,
+	protocol.ErrorBadFileHandle:   codes.InvalidArgument,
+	protocol.ErrorNoSpace:         codes.ResourceExhausted,
+	protocol.ErrorNameTooLong:     codes.InvalidArgument,
+	protocol.ErrorBusy:            codes.Unavailable,
+	protocol.ErrorCrossDevice:     codes.FailedPrecondition,
+	protocol.ErrorLoop:            codes.FailedPrecondition,
+	protocol.ErrorNotSupported:    codes.Unimplemented,
+	protocol.ErrorReadOnly:        codes.PermissionDenied,
+	protocol.ErrorVersionMismatch: codes.FailedPrecondition,
+	protocol.ErrorNoAttribute:     codes.NotFound,
+	protocol.ErrorLocked:          codes.Aborted,
+	protocol.ErrorQuotaExceeded:   codes.ResourceExhausted,
+}
+
+type grpcServer struct {
+	config   *Config
+	notifier *Notifier
+	locks    *LockManager
+	quotas   *QuotaManager
+}
+
+// errConnectionClosing is returned for calls that arrive after their
+// connection ended.
+var errConnectionClosing = fmt.Errorf("connection is closing: %w", syscall.EBUSY)
+
+// grpcConnection is the state of a single gRPC connection.
+type grpcConnection struct {
+	mutex   sync.Mutex
+	handler *FileboxMessageHandler // created by the first authenticated call
+	closing bool                   // the connection ended, so new calls are rejected
+	calls   sync.WaitGroup         // the calls that are being served
+}
+
+type grpcConnectionKey struct{}
+
+// TagConn attaches a grpcConnection to the context of every new connection.
+// The contexts of the calls on the connection are derived from it.
+func (server *grpcServer) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, grpcConnectionKey{}, &grpcConnection{})
+}
+
+// HandleConn closes the files of a connection when it ends. Calls that are
+// still being served finish first, so they don't use files that are closed
+// under them.
+func (server *grpcServer) HandleConn(ctx context.Context, connectionStats stats.ConnStats) {
+	if _, ok := connectionStats.(*stats.ConnEnd); !ok {
+		return
+	}
+
+	connection := ctx.Value(grpcConnectionKey{}).(*grpcConnection)
+
+	connection.mutex.Lock()
+	connection.closing = true
+	handler := connection.handler
+	connection.mutex.Unlock()
+
+	connection.calls.Wait()
+
+	if handler != nil {
+		handler.Close()
+	}
+}
+
+func (server *grpcServer) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (server *grpcServer) HandleRPC(ctx context.Context, rpcStats stats.RPCStats) {}
+
+// messageHandler authenticates a call, and returns the message handler of
+// its connection. All the calls on a connection must use the same username.
+// The call is counted as in flight until done is called.
+func (server *grpcServer) messageHandler(ctx context.Context) (handler *FileboxMessageHandler, done func(), err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	request := protocol.AuthenticateRequest{}
+	if values := md.Get("username"); len(values) > 0 {
+		request.Username = values[0]
+	}
+	if values := md.Get("token"); len(values) > 0 {
+		request.Token = values[0]
+	}
+
+	username, err := server.config.Authenticator.Authenticate(request)
+	if err != nil {
+		log.WithField("username", request.Username).WithError(err).Warn("gRPC authentication failed")
+		return nil, nil, err
+	}
+
+	connection, ok := ctx.Value(grpcConnectionKey{}).(*grpcConnection)
+	if !ok {
+		return nil, nil, errors.New("call without a connection")
+	}
+
+	connection.mutex.Lock()
+	defer connection.mutex.Unlock()
+
+	if connection.closing {
+		return nil, nil, errConnectionClosing
+	}
+
+	if connection.handler == nil {
+		connection.handler = &FileboxMessageHandler{
+			BasePath: server.config.BasePath,
+			Username: username,
+			Policy:   server.config.Policy,
+			ReadOnly: server.config.ReadOnly,
+			Quota:    server.config.Quota,
+			Notifier: server.notifier,
+			Locks:    server.locks,
+			Quotas:   server.quotas,
+
+			AllXattrNamespaces: server.config.AllXattrNamespaces,
+			AllowSetid:         server.config.AllowSetid,
+		}
+	} else if connection.handler.Username != username {
+		return nil, nil, fmt.Errorf("connection belongs to another user: %w", ErrAuthenticationFailed)
+	}
+
+	connection.calls.Add(1)
+	return connection.handler, connection.calls.Done, nil
+}
+
+// call serves a single request in its protobuf form.
+func (server *grpcServer) call(ctx context.Context, wire proto.Message) (proto.Message, error) {
+	handler, done, err := server.messageHandler(ctx)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	defer done()
+
+	request, err := protocol.FromProtobuf(wire)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data, err := serveRequest(handler, request)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	if val := reflect.ValueOf(data); !val.IsValid() || val.IsNil() {
+		return &pb.EmptyResponse{}, nil
+	}
+
+	response, _, err := protocol.ToProtobuf(data)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return response, nil
+}
+
+// readFileStream serves ReadFileStream: it reads a range of a file, and sends
+// it in chunks. If the size of the request isn't positive, it reads until the
+// end of the file.
+func (server *grpcServer) readFileStream(stream grpc.ServerStream) error {
+	request := &pb.ReadFileRequest{}
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+
+	for remaining := request.Size; remaining > 0 || request.Size <= 0; {
+		chunk := *request
+		if chunk.Size = grpcChunkSize; remaining > 0 && remaining < grpcChunkSize {
+			chunk.Size = remaining
+		}
+
+		response, err := server.call(stream.Context(), &chunk)
+		if err != nil {
+			return err
+		}
+
+		data := response.(*pb.ReadFileResponse)
+		if data.BytesRead == 0 {
+			return nil
+		}
+
+		data.Data = data.Data[:data.BytesRead]
+		if err := stream.SendMsg(data); err != nil {
+			return err
+		}
+
+		request.Offset += data.BytesRead
+		remaining -= data.BytesRead
+	}
+
+	return nil
+}
+
+// writeFileStream serves WriteFileStream: every message that the client sends
+// is written at its own offset, and the total number of bytes that were
+// written is returned when the client closes the stream.
+func (server *grpcServer) writeFileStream(stream grpc.ServerStream) error {
+	total := &pb.WriteFileResponse{}
+
+	for {
+		request := &pb.WriteFileRequest{}
+		if err := stream.RecvMsg(request); err == io.EOF {
+			return stream.SendMsg(total)
+		} else if err != nil {
+			return err
+		}
+
+		response, err := server.call(stream.Context(), request)
+		if err != nil {
+			return err
+		}
+
+		total.BytesWritten += response.(*pb.WriteFileResponse).BytesWritten
+	}
+}
+
+// grpcError converts an error to a gRPC status, and sends its protocol error
+// code in the trailer.
+func grpcError(ctx context.Context, err error) error {
+	code := protocol.ErrorCodeOf(err)
+	grpc.SetTrailer(ctx, metadata.Pairs("filebox-error-code", strconv.Itoa(int(code))))
+
+	grpcCode, ok := grpcErrorCodes[code]
+	if !ok {
+		grpcCode = codes.Unknown
+	}
+
+	return status.Error(grpcCode, err.Error())
+}
+
+// serviceDesc describes the Filebox service to gRPC. It's what protoc would
+// generate from filebox.proto.
+func (server *grpcServer) serviceDesc() *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: grpcServiceName,
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName: "ReadFileStream",
+				Handler: func(_ interface{}, stream grpc.ServerStream) error {
+					return server.readFileStream(stream)
+				},
+				ServerStreams: true,
+			},
+			{
+				StreamName: "WriteFileStream",
+				Handler: func(_ interface{}, stream grpc.ServerStream) error {
+					return server.writeFileStream(stream)
+				},
+				ClientStreams: true,
+			},
+		},
+		Metadata: "filebox.proto",
+	}
+
+	for _, method := range grpcMethods {
+		method := method
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: method.name,
+			Handler: func(srv interface{}, ctx context.Context, decode func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				request := proto.Clone(method.request)
+				request.Reset()
+				if err := decode(request); err != nil {
+					return nil, err
+				}
+
+				if interceptor == nil {
+					return server.call(ctx, request)
+				}
+
+				info := &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: "/" + grpcServiceName + "/" + method.name,
+				}
+				return interceptor(ctx, request, info, func(ctx context.Context, request interface{}) (interface{}, error) {
+					return server.call(ctx, request.(proto.Message))
+				})
+			},
+		})
+	}
+
+	return desc
+}
+
+// runGRPCServer serves the gRPC service on its own port. Changes that are made
+// through it are broadcast by notifier, and its locks and storage are kept by
+// locks and quotas, like the ones of Filebox clients.
+func runGRPCServer(config *Config, notifier *Notifier, locks *LockManager, quotas *QuotaManager) {
+	listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", config.GRPCPort))
+	if err != nil {
+		log.WithError(err).WithField("port", config.GRPCPort).Error("net.Listen() failed")
+		return
+	}
+
+	log.WithField("port", config.GRPCPort).Info("Started gRPC service.")
+
+	if err := newGRPCServer(config, notifier, locks, quotas).Serve(listener); err != nil {
+		log.WithError(err).Error("grpcServer.Serve() failed")
+	}
+}
+
+// newGRPCServer creates a gRPC server with the Filebox service.
+func newGRPCServer(config *Config, notifier *Notifier, locks *LockManager, quotas *QuotaManager) *grpc.Server {
+	server := &grpcServer{config: config, notifier: notifier, locks: locks, quotas: quotas}
+	options := []grpc.ServerOption{grpc.StatsHandler(server)}
+	if config.TLSConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(config.TLSConfig)))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	grpcServer.RegisterService(server.serviceDesc(), server)
+	return grpcServer
+}
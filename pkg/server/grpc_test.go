@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"github.com/alongubkin/filebox/pkg/protocol/pb"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGRPCServer serves the gRPC service on an in-memory listener, and
+// returns a connection to it. stop stops the server and removes its directory.
+func startGRPCServer(t *testing.T, config Config) (connection *grpc.ClientConn, stop func()) {
+	log.SetLevel(log.FatalLevel)
+	protocol.Init()
+
+	directory, err := ioutil.TempDir("", "filebox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.BasePath = directory
+
+	listener := bufconn.Listen(1 << 20)
+	server := newGRPCServer(&config, NewNotifier(), NewLockManager(false), nil)
+	go server.Serve(listener)
+
+	connection, err = grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		server.Stop()
+		os.RemoveAll(directory)
+		t.Fatal(err)
+	}
+
+	return connection, func() {
+		connection.Close()
+		server.Stop()
+		os.RemoveAll(directory)
+	}
+}
+
+func grpcMethod(name string) string {
+	return "/" + grpcServiceName + "/" + name
+}
+
+// TestGRPCServiceDesc checks the hand-built service description against the
+// service in filebox.proto.
+func TestGRPCServiceDesc(t *testing.T) {
+	source, err := ioutil.ReadFile(filepath.Join("..", "protocol", "pb", "filebox.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type rpc struct {
+		request       string
+		clientStreams bool
+		serverStreams bool
+	}
+
+	rpcs := make(map[string]rpc)
+	pattern := regexp.MustCompile(`rpc (\w+)\((stream )?(\w+)\) returns \((stream )?(\w+)\)`)
+	for _, match := range pattern.FindAllStringSubmatch(string(source), -1) {
+		rpcs[match[1]] = rpc{request: match[3], clientStreams: match[2] != "", serverStreams: match[4] != ""}
+	}
+
+	desc := (&grpcServer{}).serviceDesc()
+	if len(desc.Methods)+len(desc.Streams) != len(rpcs) {
+		t.Errorf("the service has %d methods and %d streams, filebox.proto has %d rpcs", len(desc.Methods), len(desc.Streams), len(rpcs))
+	}
+
+	for _, method := range grpcMethods {
+		rpc, ok := rpcs[method.name]
+		if !ok {
+			t.Errorf("%s isn't in filebox.proto", method.name)
+			continue
+		}
+
+		if request := reflect.TypeOf(method.request).Elem().Name(); rpc.request != request || rpc.clientStreams || rpc.serverStreams {
+			t.Errorf("%s takes a %s, but filebox.proto declares %+v", method.name, request, rpc)
+		}
+	}
+
+	for _, stream := range desc.Streams {
+		rpc, ok := rpcs[stream.StreamName]
+		if !ok {
+			t.Errorf("%s isn't in filebox.proto", stream.StreamName)
+			continue
+		}
+
+		if rpc.clientStreams != stream.ClientStreams || rpc.serverStreams != stream.ServerStreams {
+			t.Errorf("%s streams %v/%v, but filebox.proto declares %+v", stream.StreamName, stream.ClientStreams, stream.ServerStreams, rpc)
+		}
+	}
+}
+
+func TestGRPCUnaryMethods(t *testing.T) {
+	connection, stop := startGRPCServer(t, Config{})
+	defer stop()
+
+	ctx := context.Background()
+
+	if err := connection.Invoke(ctx, grpcMethod("CreateFile"), &pb.CreateFileRequest{Path: "/file"}, &pb.EmptyResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	opened := &pb.OpenFileResponse{}
+	if err := connection.Invoke(ctx, grpcMethod("OpenFile"), &pb.OpenFileRequest{Path: "/file", Flags: int64(os.O_RDWR)}, opened); err != nil {
+		t.Fatal(err)
+	}
+
+	written := &pb.WriteFileResponse{}
+	if err := connection.Invoke(ctx, grpcMethod("WriteFile"), &pb.WriteFileRequest{FileHandle: opened.FileHandle, Data: []byte("hello")}, written); err != nil {
+		t.Fatal(err)
+	}
+	if written.BytesWritten != 5 {
+		t.Errorf("WriteFile wrote %d bytes, want 5", written.BytesWritten)
+	}
+
+	read := &pb.ReadFileResponse{}
+	if err := connection.Invoke(ctx, grpcMethod("ReadFile"), &pb.ReadFileRequest{FileHandle: opened.FileHandle, Size: 100}, read); err != nil {
+		t.Fatal(err)
+	}
+	if string(read.Data[:read.BytesRead]) != "hello" {
+		t.Errorf("ReadFile read %q, want %q", read.Data[:read.BytesRead], "hello")
+	}
+
+	listing := &pb.ReadDirectoryResponse{}
+	if err := connection.Invoke(ctx, grpcMethod("ReadDirectory"), &pb.ReadDirectoryRequest{Path: "/"}, listing); err != nil {
+		t.Fatal(err)
+	}
+	if len(listing.Files) != 1 || listing.Files[0].Name != "file" || listing.Files[0].Size != 5 {
+		t.Errorf("ReadDirectory listed %v, want the file", listing.Files)
+	}
+
+	if err := connection.Invoke(ctx, grpcMethod("CloseFile"), &pb.CloseFileRequest{FileHandle: opened.FileHandle}, &pb.EmptyResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every method decodes its own request type and reaches the handler.
+	for _, method := range grpcMethods {
+		request := proto.Clone(method.request)
+		request.Reset()
+
+		err := connection.Invoke(ctx, grpcMethod(method.name), request, &pb.EmptyResponse{})
+		if code := status.Code(err); code == codes.Unimplemented || code == codes.Internal {
+			t.Errorf("%s failed: %v", method.name, err)
+		}
+	}
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	connection, stop := startGRPCServer(t, Config{Authenticator: &Authenticator{Tokens: map[string]string{"user": "token"}}})
+	defer stop()
+
+	tests := []struct {
+		name     string
+		token    string
+		path     string
+		code     codes.Code
+		protocol protocol.ErrorCode
+	}{
+		{"not found", "token", "/missing", codes.NotFound, protocol.ErrorNotFound},
+		{"bad credentials", "wrong", "/", codes.PermissionDenied, protocol.ErrorPermission},
+		{"escape", "token", "/../..", codes.PermissionDenied, protocol.ErrorPermission},
+	}
+
+	for _, test := range tests {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "username", "user", "token", test.token)
+
+		var trailer metadata.MD
+		err := connection.Invoke(ctx, grpcMethod("GetFileAttributes"),
+			&pb.GetFileAttributesRequest{Path: test.path, FileHandle: ^uint64(0)}, &pb.GetFileAttributesResponse{}, grpc.Trailer(&trailer))
+
+		if code := status.Code(err); code != test.code {
+			t.Errorf("%s: got %v, want %v", test.name, code, test.code)
+		}
+
+		if values := trailer.Get("filebox-error-code"); len(values) != 1 || values[0] != strconv.Itoa(int(test.protocol)) {
+			t.Errorf("%s: the filebox-error-code trailer is %v, want %d", test.name, values, test.protocol)
+		}
+	}
+}
+
+func TestGRPCStreams(t *testing.T) {
+	connection, stop := startGRPCServer(t, Config{})
+	defer stop()
+
+	ctx := context.Background()
+	desc := (&grpcServer{}).serviceDesc()
+
+	if err := connection.Invoke(ctx, grpcMethod("CreateFile"), &pb.CreateFileRequest{Path: "/file"}, &pb.EmptyResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	opened := &pb.OpenFileResponse{}
+	if err := connection.Invoke(ctx, grpcMethod("OpenFile"), &pb.OpenFileRequest{Path: "/file", Flags: int64(os.O_RDWR)}, opened); err != nil {
+		t.Fatal(err)
+	}
+
+	// Larger than a few chunks of ReadFileStream.
+	data := make([]byte, 3*grpcChunkSize+100)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	writer, err := connection.NewStream(ctx, &desc.Streams[1], grpcMethod("WriteFileStream"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every message is written at its own offset, so send them out of order.
+	half := int64(len(data) / 2)
+	for _, part := range []*pb.WriteFileRequest{
+		{FileHandle: opened.FileHandle, Offset: half, Data: data[half:]},
+		{FileHandle: opened.FileHandle, Offset: 0, Data: data[:half]},
+	} {
+		if err := writer.SendMsg(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	written := &pb.WriteFileResponse{}
+	if err := writer.RecvMsg(written); err != nil {
+		t.Fatal(err)
+	}
+	if written.BytesWritten != int64(len(data)) {
+		t.Errorf("WriteFileStream wrote %d bytes, want %d", written.BytesWritten, len(data))
+	}
+
+	readStream := func(request *pb.ReadFileRequest) []byte {
+		reader, err := connection.NewStream(ctx, &desc.Streams[0], grpcMethod("ReadFileStream"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := reader.SendMsg(request); err != nil {
+			t.Fatal(err)
+		}
+		if err := reader.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+
+		var read []byte
+		for {
+			chunk := &pb.ReadFileResponse{}
+			if err := reader.RecvMsg(chunk); err == io.EOF {
+				return read
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(chunk.Data) > grpcChunkSize {
+				t.Errorf("ReadFileStream sent a chunk of %d bytes", len(chunk.Data))
+			}
+			read = append(read, chunk.Data...)
+		}
+	}
+
+	// A size that isn't positive reads until the end of the file.
+	if read := readStream(&pb.ReadFileRequest{FileHandle: opened.FileHandle}); !bytes.Equal(read, data) {
+		t.Errorf("ReadFileStream read %d bytes, want the %d bytes that were written", len(read), len(data))
+	}
+
+	if read := readStream(&pb.ReadFileRequest{FileHandle: opened.FileHandle, Offset: 10, Size: grpcChunkSize + 5}); !bytes.Equal(read, data[10:10+grpcChunkSize+5]) {
+		t.Errorf("ReadFileStream of a range read %d bytes, want %d", len(read), grpcChunkSize+5)
+	}
+
+	// Errors of a stream carry the error code too.
+	reader, err := connection.NewStream(ctx, &desc.Streams[0], grpcMethod("ReadFileStream"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reader.SendMsg(&pb.ReadFileRequest{FileHandle: opened.FileHandle + 100}); err != nil {
+		t.Fatal(err)
+	}
+	reader.CloseSend()
+
+	err = reader.RecvMsg(&pb.ReadFileResponse{})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("ReadFileStream of a bad file handle: got %v, want %v", err, codes.InvalidArgument)
+	}
+	if values := reader.Trailer().Get("filebox-error-code"); len(values) != 1 || values[0] != strconv.Itoa(int(protocol.ErrorBadFileHandle)) {
+		t.Errorf("the filebox-error-code trailer of the stream is %v, want %d", values, protocol.ErrorBadFileHandle)
+	}
+}
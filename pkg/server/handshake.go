@@ -0,0 +1,72 @@
+package server
+
+import (
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// Capabilities are the optional protocol features that the server always
+// supports. The others depend on the configuration; see serverCapabilities.
+var Capabilities = protocol.Capabilities{
+	protocol.CapabilityNotifications,
+	protocol.CapabilitySymlinks,
+}
+
+// serverCapabilities returns the capabilities that the server announces.
+// Locks are only announced if there's a lock manager.
+func serverCapabilities(locks *LockManager) protocol.Capabilities {
+	capabilities := append(protocol.Capabilities{}, Capabilities...)
+	if locks != nil {
+		capabilities = append(capabilities, protocol.CapabilityLocks)
+	}
+
+	return capabilities
+}
+
+// handshake runs the beginning of a connection: the hello exchange, in which
+// the protocol version and the capabilities are negotiated, followed by
+// authentication. It returns the username of the client and the result of
+// the negotiation.
+//
+// Clients that predate the hello exchange start with an AuthenticateRequest.
+// They are treated as LegacyVersion clients without any capabilities.
+func handshake(authenticator *Authenticator, capabilities protocol.Capabilities, encoder protocol.Encoder, decoder protocol.Decoder) (string, protocol.HelloResponse, error) {
+	message := &protocol.Message{}
+	if err := decoder.Decode(message); err != nil {
+		return "", protocol.HelloResponse{}, err
+	}
+
+	hello := protocol.HelloResponse{Version: protocol.LegacyVersion}
+
+	if request, ok := message.Data.(protocol.HelloRequest); ok {
+		var err error
+		hello, err = protocol.Negotiate(request, capabilities)
+
+		response := &protocol.Message{
+			MessageID:  message.MessageID,
+			IsResponse: true,
+			Data:       &hello,
+		}
+
+		if err != nil {
+			response.Data = &protocol.EmptyResponse{}
+			response.ErrorCode = protocol.ErrorCodeOf(err)
+			response.ErrorMessage = err.Error()
+		}
+
+		if encodeErr := encoder.Encode(response); encodeErr != nil {
+			return "", hello, encodeErr
+		}
+
+		if err != nil {
+			return "", hello, err
+		}
+
+		message = &protocol.Message{}
+		if err := decoder.Decode(message); err != nil {
+			return "", hello, err
+		}
+	}
+
+	username, err := authenticate(authenticator, encoder, message)
+	return username, hello, err
+}
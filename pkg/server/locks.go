@@ -0,0 +1,411 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// lockEOF is the end of the locks that extend to the end of the file,
+// however long it grows.
+const lockEOF = math.MaxInt64
+
+// LockManager keeps the locks that clients hold on files. The locks are
+// shared by all the connections, so they work across clients. Files are
+// identified by their identity on the disk rather than by their path, so a
+// rename or a hard link doesn't get around a lock.
+//
+// Locks are advisory: they only conflict with other locks. If mandatory is
+// set, reads and writes also fail when they conflict with a byte range lock
+// of another connection.
+type LockManager struct {
+	mandatory bool
+
+	mutex sync.Mutex
+	files []*lockedFile
+}
+
+type lockedFile struct {
+	info  os.FileInfo
+	locks []*fileLock
+}
+
+type fileLock struct {
+	handler    *FileboxMessageHandler
+	fileHandle uint64
+	kind       protocol.LockKind
+	lockType   protocol.LockType
+	owner      uint64
+	start      int64
+	end        int64 // exclusive
+}
+
+func NewLockManager(mandatory bool) *LockManager {
+	return &LockManager{mandatory: mandatory}
+}
+
+// sameOwner returns true if both locks belong to the same owner: the same
+// process on the same connection for byte range locks, and the same open
+// file for whole file locks.
+func (lock *fileLock) sameOwner(other *fileLock) bool {
+	if lock.handler != other.handler || lock.kind != other.kind {
+		return false
+	}
+
+	if lock.kind == protocol.LockFile {
+		return lock.fileHandle == other.fileHandle
+	}
+
+	return lock.owner == other.owner
+}
+
+func (lock *fileLock) overlaps(start int64, end int64) bool {
+	return lock.start < end && start < lock.end
+}
+
+func (lock *fileLock) conflicts(other *fileLock) bool {
+	return lock.kind == other.kind &&
+		!lock.sameOwner(other) &&
+		lock.overlaps(other.start, other.end) &&
+		(lock.lockType == protocol.LockExclusive || other.lockType == protocol.LockExclusive)
+}
+
+// file returns the locks of a file. If the file has no locks, it returns nil,
+// or adds the file if create is set. manager.mutex must be locked.
+func (manager *LockManager) file(info os.FileInfo, create bool) *lockedFile {
+	for _, file := range manager.files {
+		if os.SameFile(file.info, info) {
+			return file
+		}
+	}
+
+	if !create {
+		return nil
+	}
+
+	file := &lockedFile{info: info}
+	manager.files = append(manager.files, file)
+	return file
+}
+
+// prune forgets the files that have no locks left. manager.mutex must be locked.
+func (manager *LockManager) prune() {
+	files := manager.files[:0]
+	for _, file := range manager.files {
+		if len(file.locks) > 0 {
+			files = append(files, file)
+		}
+	}
+
+	for i := len(files); i < len(manager.files); i++ {
+		manager.files[i] = nil
+	}
+	manager.files = files
+}
+
+// Lock places a lock, or fails with EAGAIN if it conflicts with a lock of
+// another owner. Locks of the same owner in its range are replaced, so a
+// lock can be upgraded, downgraded or split like with fcntl(2).
+func (manager *LockManager) Lock(info os.FileInfo, lock *fileLock) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	file := manager.file(info, true)
+	for _, other := range file.locks {
+		if lock.conflicts(other) {
+			manager.prune()
+			return syscall.EAGAIN
+		}
+	}
+
+	file.locks = append(unlockRange(file.locks, lock), lock)
+	return nil
+}
+
+// Unlock removes the range of lock from the locks of its owner.
+func (manager *LockManager) Unlock(info os.FileInfo, lock *fileLock) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if file := manager.file(info, false); file != nil {
+		file.locks = unlockRange(file.locks, lock)
+		manager.prune()
+	}
+}
+
+// Test returns a lock that conflicts with lock, or nil if it could be placed.
+func (manager *LockManager) Test(info os.FileInfo, lock *fileLock) *fileLock {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if file := manager.file(info, false); file != nil {
+		for _, other := range file.locks {
+			if lock.conflicts(other) {
+				conflict := *other
+				return &conflict
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check enforces mandatory locks: it fails with EAGAIN if a byte range lock
+// of another connection conflicts with reading or writing a range of a file.
+func (manager *LockManager) Check(handler *FileboxMessageHandler, info os.FileInfo, start int64, end int64, write bool) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if file := manager.file(info, false); file != nil {
+		for _, other := range file.locks {
+			if other.handler != handler && other.kind == protocol.LockRange && other.overlaps(start, end) &&
+				(write || other.lockType == protocol.LockExclusive) {
+				return syscall.EAGAIN
+			}
+		}
+	}
+
+	return nil
+}
+
+// Release removes the locks of a file handle when it's closed. Like close(2),
+// it removes the whole file lock of the handle, and every byte range lock on
+// the file of the owners that locked through the handle, even the ones that
+// they placed through other handles.
+func (manager *LockManager) Release(handler *FileboxMessageHandler, fileHandle uint64) {
+	if manager == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for _, file := range manager.files {
+		owners := make(map[uint64]bool)
+		for _, lock := range file.locks {
+			if lock.handler == handler && lock.fileHandle == fileHandle && lock.kind == protocol.LockRange {
+				owners[lock.owner] = true
+			}
+		}
+
+		locks := file.locks[:0]
+		for _, lock := range file.locks {
+			if lock.handler != handler || (lock.fileHandle != fileHandle && (lock.kind != protocol.LockRange || !owners[lock.owner])) {
+				locks = append(locks, lock)
+			}
+		}
+		file.locks = locks
+	}
+
+	manager.prune()
+}
+
+// ReleaseAll removes the locks of a connection. It's called when the
+// connection is closed.
+func (manager *LockManager) ReleaseAll(handler *FileboxMessageHandler) {
+	manager.release(func(lock *fileLock) bool {
+		return lock.handler == handler
+	})
+}
+
+func (manager *LockManager) release(match func(*fileLock) bool) {
+	if manager == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for _, file := range manager.files {
+		locks := file.locks[:0]
+		for _, lock := range file.locks {
+			if !match(lock) {
+				locks = append(locks, lock)
+			}
+		}
+		file.locks = locks
+	}
+
+	manager.prune()
+}
+
+// unlockRange removes the range of lock from the locks of its owner. Locks
+// that are partly in the range are cut, or split in two.
+func unlockRange(locks []*fileLock, lock *fileLock) []*fileLock {
+	var result []*fileLock
+
+	for _, other := range locks {
+		if !other.sameOwner(lock) || !other.overlaps(lock.start, lock.end) {
+			result = append(result, other)
+			continue
+		}
+
+		if other.start < lock.start {
+			before := *other
+			before.end = lock.start
+			result = append(result, &before)
+		}
+
+		if lock.end < other.end {
+			after := *other
+			after.start = lock.end
+			result = append(result, &after)
+		}
+	}
+
+	return result
+}
+
+// newLock validates the range of a lock request, and creates the lock that
+// it describes on an open file.
+func (handler *FileboxMessageHandler) newLock(fileHandle uint64, kind protocol.LockKind, lockType protocol.LockType, start int64, length int64, owner uint64) (*fileLock, os.FileInfo, error) {
+	if handler.Locks == nil {
+		return nil, nil, syscall.ENOTSUP
+	}
+
+	file, ok := handler.fileHandles.Load(fileHandle)
+	if !ok {
+		log.WithField("fh", fileHandle).Error("Invalid file handle in lock request")
+		return nil, nil, syscall.EBADF
+	}
+
+	lock := &fileLock{
+		handler:    handler,
+		fileHandle: fileHandle,
+		kind:       kind,
+		lockType:   lockType,
+		owner:      owner,
+		start:      start,
+		end:        lockEOF,
+	}
+
+	switch kind {
+	case protocol.LockRange:
+		if start < 0 || length < 0 {
+			return nil, nil, fmt.Errorf("invalid lock range %d+%d: %w", start, length, os.ErrInvalid)
+		}
+
+		if length > 0 && start <= lockEOF-length {
+			lock.end = start + length
+		}
+
+	case protocol.LockFile:
+		lock.start, lock.owner = 0, 0
+
+	default:
+		return nil, nil, fmt.Errorf("invalid lock kind %d: %w", kind, os.ErrInvalid)
+	}
+
+	info, err := file.(*os.File).Stat()
+	if err != nil {
+		log.WithField("path", file.(*os.File).Name()).WithError(err).Warn("file.Stat() failed")
+		return nil, nil, err
+	}
+
+	return lock, info, nil
+}
+
+// checkMandatoryLocks fails with EAGAIN if mandatory locks are enabled, and
+// a lock of another connection conflicts with reading or writing the range
+// [start, end) of a file. If the file can't be stat'ed, the check is skipped
+// and the operation itself reports the error.
+func (handler *FileboxMessageHandler) checkMandatoryLocks(stat func() (os.FileInfo, error), start int64, end int64, write bool) error {
+	if handler.Locks == nil || !handler.Locks.mandatory {
+		return nil
+	}
+
+	info, err := stat()
+	if err != nil {
+		return nil
+	}
+
+	return handler.Locks.Check(handler, info, start, end, write)
+}
+
+// lockLength converts the end of a lock back to the length in the protocol.
+func lockLength(lock *fileLock) int64 {
+	if lock.end == lockEOF {
+		return 0
+	}
+
+	return lock.end - lock.start
+}
+
+func (handler *FileboxMessageHandler) Lock(request protocol.LockRequest) error {
+	log.WithFields(log.Fields{
+		"fh":     request.FileHandle,
+		"kind":   request.Kind,
+		"type":   request.Type,
+		"start":  request.Start,
+		"length": request.Length,
+	}).Trace("Locking file")
+
+	if request.Type != protocol.LockShared && request.Type != protocol.LockExclusive {
+		return fmt.Errorf("invalid lock type %d: %w", request.Type, os.ErrInvalid)
+	}
+
+	lock, info, err := handler.newLock(request.FileHandle, request.Kind, request.Type, request.Start, request.Length, request.Owner)
+	if err != nil {
+		return err
+	}
+
+	if err := handler.Locks.Lock(info, lock); err != nil {
+		// Conflicts are expected, clients retry or give up.
+		log.WithField("fh", request.FileHandle).WithError(err).Trace("Lock conflicts with another lock")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) Unlock(request protocol.UnlockRequest) error {
+	log.WithFields(log.Fields{
+		"fh":     request.FileHandle,
+		"kind":   request.Kind,
+		"start":  request.Start,
+		"length": request.Length,
+	}).Trace("Unlocking file")
+
+	lock, info, err := handler.newLock(request.FileHandle, request.Kind, protocol.LockNone, request.Start, request.Length, request.Owner)
+	if err != nil {
+		return err
+	}
+
+	handler.Locks.Unlock(info, lock)
+	return nil
+}
+
+func (handler *FileboxMessageHandler) TestLock(request protocol.TestLockRequest) (*protocol.TestLockResponse, error) {
+	log.WithFields(log.Fields{
+		"fh":     request.FileHandle,
+		"kind":   request.Kind,
+		"type":   request.Type,
+		"start":  request.Start,
+		"length": request.Length,
+	}).Trace("Testing lock")
+
+	if request.Type != protocol.LockShared && request.Type != protocol.LockExclusive {
+		return nil, fmt.Errorf("invalid lock type %d: %w", request.Type, os.ErrInvalid)
+	}
+
+	lock, info, err := handler.newLock(request.FileHandle, request.Kind, request.Type, request.Start, request.Length, request.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	conflict := handler.Locks.Test(info, lock)
+	if conflict == nil {
+		return &protocol.TestLockResponse{Type: protocol.LockNone}, nil
+	}
+
+	return &protocol.TestLockResponse{
+		Type:   conflict.lockType,
+		Start:  conflict.start,
+		Length: lockLength(conflict),
+	}, nil
+}
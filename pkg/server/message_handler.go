@@ -1,34 +1,110 @@
 package server
 
 import (
+	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 
 	"github.com/alongubkin/filebox/pkg/protocol"
 	log "github.com/sirupsen/logrus"
 )
 
+// FileboxMessageHandler handles the requests of a single client connection.
+// File handles are owned by the connection that opened them, so a client
+// can't use or close the handles of another client.
 type FileboxMessageHandler struct {
-	BasePath string
-
-	// FUTURE: Automatically close handles if their client is disconnected.
-	fileHandles    sync.Map
+	// Accessed atomically, so it must be first for 64-bit alignment on 32-bit platforms.
 	nextFileHandle uint64
+
+	BasePath     string
+	Username     string                // the authenticated user of the connection
+	Capabilities protocol.Capabilities // negotiated in the hello exchange
+	Policy       *Policy               // access rules, or nil to allow everything
+	ReadOnly     bool                  // reject every request that modifies the shared directory
+	Quota        uint64                // caps the size that Statfs reports, in bytes, or 0 for no cap
+
+	// AllXattrNamespaces allows clients to change extended attributes outside
+	// the "user." namespace.
+	AllXattrNamespaces bool
+
+	// AllowSetid allows clients to set the setuid and setgid bits.
+	AllowSetid bool
+
+	// Notifier broadcasts the changes of this connection to the other ones.
+	Notifier *Notifier
+
+	// Locks keeps the file locks of all the connections. If it's nil, lock
+	// requests are rejected.
+	Locks *LockManager
+
+	// Quotas keeps the storage quotas of all the connections, and the
+	// storage that the shared directory uses for Quota. It's nil if there
+	// are neither.
+	Quotas *QuotaManager
+
+	rootOnce sync.Once
+	rootPath string
+	rootErr  error
+
+	fileHandles sync.Map
+
+	// quotaHandles has the *QuotaHandle of every file handle, if there are
+	// quotas.
+	quotaHandles sync.Map
+
+	// writtenFiles has the paths of the file handles with writes that the
+	// other clients weren't notified about yet, by file handle.
+	writtenFiles sync.Map
+}
+
+// isFileHandle returns true if the request refers to an open file handle,
+// rather than to a path.
+func (handler *FileboxMessageHandler) isFileHandle(fileHandle uint64) bool {
+	return fileHandle <= atomic.LoadUint64(&handler.nextFileHandle)
+}
+
+// Close closes all the files that are still open by the connection.
+// It's called after the connection is closed.
+func (handler *FileboxMessageHandler) Close() {
+	handler.Locks.ReleaseAll(handler)
+
+	handler.writtenFiles.Range(func(fileHandle, filePath interface{}) bool {
+		handler.notifyWritten(fileHandle.(uint64))
+		return true
+	})
+
+	handler.fileHandles.Range(func(fileHandle, file interface{}) bool {
+		log.WithField("fh", fileHandle).Tracef("Closing leftover file %s", file.(*os.File).Name())
+
+		file.(*os.File).Close()
+		handler.closeQuotaHandle(fileHandle.(uint64))
+		handler.fileHandles.Delete(fileHandle)
+		return true
+	})
 }
 
 func (handler *FileboxMessageHandler) OpenFile(request protocol.OpenFileRequest) (*protocol.OpenFileResponse, error) {
-	file, err := openFile(path.Join(handler.BasePath, request.Path),
-		request.Flags & ^os.O_EXCL, 00777)
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("OpenFile rejected")
+		return nil, err
+	}
+
+	file, err := openFile(filePath, request.Flags & ^os.O_EXCL, 00777)
 	if err != nil {
 		log.WithField("path", request.Path).WithError(err).Error("OpenFile failed")
 		return nil, err
 	}
 
 	fileHandle := atomic.AddUint64(&handler.nextFileHandle, 1)
+	handler.openQuotaHandle(fileHandle, filePath)
 	handler.fileHandles.Store(fileHandle, file)
 
 	log.WithFields(log.Fields{
@@ -45,7 +121,15 @@ func (handler *FileboxMessageHandler) ReadFile(request protocol.ReadFileRequest)
 	file, ok := handler.fileHandles.Load(request.FileHandle)
 	if !ok {
 		log.WithField("fh", request.FileHandle).Error("Invalid file handle in ReadFile request")
-		return nil, os.ErrInvalid
+		return nil, syscall.EBADF
+	}
+
+	if request.Size < 0 || request.Size > protocol.MaxChunkSize {
+		log.WithFields(log.Fields{
+			"fh":   request.FileHandle,
+			"size": request.Size,
+		}).Warn("ReadFile rejected")
+		return nil, fmt.Errorf("read size must be between 0 and %d bytes: %w", protocol.MaxChunkSize, os.ErrInvalid)
 	}
 
 	log.WithFields(log.Fields{
@@ -54,6 +138,11 @@ func (handler *FileboxMessageHandler) ReadFile(request protocol.ReadFileRequest)
 		"size":   request.Size,
 	}).Tracef("Reading file %s", file.(*os.File).Name())
 
+	if err := handler.checkMandatoryLocks(file.(*os.File).Stat, request.Offset, request.Offset+int64(request.Size), false); err != nil {
+		log.WithField("fh", request.FileHandle).WithError(err).Warn("ReadFile rejected")
+		return nil, err
+	}
+
 	buff := make([]byte, request.Size)
 
 	bytesRead, err := file.(*os.File).ReadAt(buff, request.Offset)
@@ -67,39 +156,103 @@ func (handler *FileboxMessageHandler) ReadFile(request protocol.ReadFileRequest)
 	}
 
 	return &protocol.ReadFileResponse{
-		Data:      buff,
+		Data:      buff[:bytesRead],
 		BytesRead: bytesRead,
 	}, nil
 }
 
 func (handler *FileboxMessageHandler) ReadDirectory(request protocol.ReadDirectoryRequest) (*protocol.ReadDirectoryResponse, error) {
-	files, err := ioutil.ReadDir(path.Join(handler.BasePath, request.Path))
+	directoryPath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ReadDirectory rejected")
+		return nil, err
+	}
+
+	names, err := readDirectoryNames(directoryPath)
 	if err != nil {
 		log.WithField("path", request.Path).WithError(err).Error("ReadDir failed")
 		return nil, err
 	}
 
-	log.Tracef("Reading directory %s", request.Path)
+	log.WithFields(log.Fields{
+		"after": request.After,
+		"limit": request.Limit,
+	}).Tracef("Reading directory %s", request.Path)
+
+	// Continue after the last name of the previous page, even if that file
+	// was deleted since.
+	names = names[sort.Search(len(names), func(i int) bool { return names[i] > request.After }):]
+
+	limit := request.Limit
+	if limit > protocol.MaxDirectoryPage {
+		limit = protocol.MaxDirectoryPage
+	}
 
 	response := &protocol.ReadDirectoryResponse{}
-	for _, file := range files {
-		response.Files = append(response.Files, convertFileInfo(file))
+	for _, name := range names {
+		if limit > 0 && len(response.Files) == limit {
+			response.More = true
+			break
+		}
+
+		file, err := os.Lstat(filepath.Join(directoryPath, name))
+		if os.IsNotExist(err) {
+			// Deleted since the directory was read.
+			continue
+		} else if err != nil {
+			log.WithField("path", path.Join(request.Path, name)).WithError(err).Error("Lstat failed")
+			return nil, err
+		}
+
+		fileInfo := convertFileInfo(file)
+
+		// Clients that don't handle symbolic links see the files that they
+		// point to, like GetFileAttributes returns to them.
+		if file.Mode()&os.ModeSymlink != 0 && !handler.Capabilities.Has(protocol.CapabilitySymlinks) {
+			if target, err := handler.resolvePath(path.Join(request.Path, file.Name())); err == nil {
+				if targetInfo, err := os.Stat(target); err == nil {
+					fileInfo = convertFileInfo(targetInfo)
+					fileInfo.Name = file.Name()
+				}
+			}
+		}
+
+		response.Files = append(response.Files, fileInfo)
 	}
 
 	return response, nil
 }
 
+// readDirectoryNames returns the names of the files in a directory, sorted.
+// Unlike ioutil.ReadDir, it doesn't stat them, so reading a page of a large
+// directory only stats the files of the page.
+func readDirectoryNames(directoryPath string) ([]string, error) {
+	directory, err := os.Open(directoryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer directory.Close()
+
+	names, err := directory.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
 func (handler *FileboxMessageHandler) GetFileAttributes(request protocol.GetFileAttributesRequest) (*protocol.GetFileAttributesResponse, error) {
 	var fileInfo os.FileInfo
 	var err error
 
-	if request.FileHandle <= handler.nextFileHandle {
+	if handler.isFileHandle(request.FileHandle) {
 		log.WithField("fh", request.FileHandle).Tracef("Get file attributes %s", request.Path)
 
 		file, ok := handler.fileHandles.Load(request.FileHandle)
 		if !ok {
 			log.WithField("fh", request.FileHandle).Error("Invalid file handle in GetFileAttributes request")
-			return nil, os.ErrInvalid
+			return nil, syscall.EBADF
 		}
 
 		fileInfo, err = file.(*os.File).Stat()
@@ -107,8 +260,27 @@ func (handler *FileboxMessageHandler) GetFileAttributes(request protocol.GetFile
 			log.WithField("path", file.(*os.File).Name()).WithError(err).Warn("file.Stat() failed")
 			return nil, err
 		}
+	} else if handler.Capabilities.Has(protocol.CapabilitySymlinks) {
+		// The client follows symbolic links itself, with ReadSymlink.
+		parent, name, err := handler.resolveParent(request.Path)
+		if err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("GetFileAttributes rejected")
+			return nil, err
+		}
+
+		fileInfo, err = os.Lstat(filepath.Join(parent, name))
+		if err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("os.Lstat() failed")
+			return nil, err
+		}
 	} else {
-		fileInfo, err = os.Stat(path.Join(handler.BasePath, request.Path))
+		filePath, err := handler.resolvePath(request.Path)
+		if err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("GetFileAttributes rejected")
+			return nil, err
+		}
+
+		fileInfo, err = os.Stat(filePath)
 		if err != nil {
 			log.WithField("path", request.Path).WithError(err).Warn("os.Stat() failed")
 			return nil, err
@@ -126,10 +298,12 @@ func (handler *FileboxMessageHandler) CloseFile(request protocol.CloseFileReques
 	file, ok := handler.fileHandles.Load(request.FileHandle)
 	if !ok {
 		log.WithField("fh", request.FileHandle).Error("Invalid file handle in CloseFile request")
-		return os.ErrInvalid
+		return syscall.EBADF
 	}
 
+	handler.Locks.Release(handler, request.FileHandle)
 	file.(*os.File).Close()
+	handler.closeQuotaHandle(request.FileHandle)
 	handler.fileHandles.Delete(request.FileHandle)
 
 	return nil
@@ -138,7 +312,13 @@ func (handler *FileboxMessageHandler) CloseFile(request protocol.CloseFileReques
 func (handler *FileboxMessageHandler) CreateDirectory(request protocol.CreateDirectoryRequest) error {
 	log.WithField("mode", request.Mode).Tracef("Creating directory %s", request.Path)
 
-	err := os.Mkdir(path.Join(handler.BasePath, request.Path), os.FileMode(request.Mode))
+	directoryPath, err := handler.resolveEntry(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("CreateDirectory rejected")
+		return err
+	}
+
+	err = os.Mkdir(directoryPath, os.FileMode(request.Mode))
 	if err != nil {
 		log.WithFields(log.Fields{
 			"path": request.Path,
@@ -153,7 +333,13 @@ func (handler *FileboxMessageHandler) CreateDirectory(request protocol.CreateDir
 func (handler *FileboxMessageHandler) CreateFile(request protocol.CreateFileRequest) error {
 	log.Tracef("Creating file %s", request.Path)
 
-	file, err := os.Create(path.Join(handler.BasePath, request.Path))
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("CreateFile rejected")
+		return err
+	}
+
+	file, err := os.Create(filePath)
 	if err != nil {
 		log.WithField("path", request.Path).WithError(err).Error("CreateFile failed")
 		return err
@@ -166,12 +352,19 @@ func (handler *FileboxMessageHandler) CreateFile(request protocol.CreateFileRequ
 func (handler *FileboxMessageHandler) Rename(request protocol.RenameRequest) error {
 	log.Tracef("Renaming %s to %s", request.OldPath, request.NewPath)
 
-	err := os.Rename(
-		path.Join(handler.BasePath, request.OldPath),
-		path.Join(handler.BasePath, request.NewPath),
-	)
+	oldPath, err := handler.resolveEntry(request.OldPath)
+	if err != nil {
+		log.WithField("path", request.OldPath).WithError(err).Warn("Rename rejected")
+		return err
+	}
 
+	newPath, err := handler.resolveEntry(request.NewPath)
 	if err != nil {
+		log.WithField("path", request.NewPath).WithError(err).Warn("Rename rejected")
+		return err
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
 		log.WithFields(log.Fields{
 			"old_path": request.OldPath,
 			"new_path": request.NewPath,
@@ -185,7 +378,13 @@ func (handler *FileboxMessageHandler) Rename(request protocol.RenameRequest) err
 func (handler *FileboxMessageHandler) DeleteDirectory(request protocol.DeleteDirectoryRequest) error {
 	log.Tracef("Deleting directory %s", request.Path)
 
-	if err := os.RemoveAll(path.Join(handler.BasePath, request.Path)); err != nil {
+	directoryPath, err := handler.resolveEntry(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("DeleteDirectory rejected")
+		return err
+	}
+
+	if err := os.RemoveAll(directoryPath); err != nil {
 		log.WithField("path", request.Path).WithError(err).Error("DeleteDirectory failed")
 		return err
 	}
@@ -194,7 +393,7 @@ func (handler *FileboxMessageHandler) DeleteDirectory(request protocol.DeleteDir
 }
 
 func (handler *FileboxMessageHandler) Truncate(request protocol.TruncateRequest) error {
-	if request.FileHandle <= handler.nextFileHandle {
+	if handler.isFileHandle(request.FileHandle) {
 		log.WithFields(log.Fields{
 			"fh":   request.FileHandle,
 			"size": request.Size,
@@ -203,7 +402,12 @@ func (handler *FileboxMessageHandler) Truncate(request protocol.TruncateRequest)
 		file, ok := handler.fileHandles.Load(request.FileHandle)
 		if !ok {
 			log.WithField("fh", request.FileHandle).Error("Invalid file handle in Truncate request")
-			return os.ErrInvalid
+			return syscall.EBADF
+		}
+
+		if err := handler.checkMandatoryLocks(file.(*os.File).Stat, request.Size, lockEOF, true); err != nil {
+			log.WithField("fh", request.FileHandle).WithError(err).Warn("Truncate rejected")
+			return err
 		}
 
 		if err := file.(*os.File).Truncate(request.Size); err != nil {
@@ -214,8 +418,19 @@ func (handler *FileboxMessageHandler) Truncate(request protocol.TruncateRequest)
 			return err
 		}
 	} else {
-		err := os.Truncate(path.Join(handler.BasePath, request.Path), request.Size)
+		filePath, err := handler.resolvePath(request.Path)
 		if err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("Truncate rejected")
+			return err
+		}
+
+		stat := func() (os.FileInfo, error) { return os.Stat(filePath) }
+		if err := handler.checkMandatoryLocks(stat, request.Size, lockEOF, true); err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("Truncate rejected")
+			return err
+		}
+
+		if err := os.Truncate(filePath, request.Size); err != nil {
 			log.WithFields(log.Fields{
 				"path": request.Path,
 				"size": request.Size,
@@ -230,7 +445,13 @@ func (handler *FileboxMessageHandler) Truncate(request protocol.TruncateRequest)
 func (handler *FileboxMessageHandler) DeleteFile(request protocol.DeleteFileRequest) error {
 	log.Tracef("Deleting file %s", request.Path)
 
-	if err := os.Remove(path.Join(handler.BasePath, request.Path)); err != nil {
+	filePath, err := handler.resolveEntry(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("DeleteFile rejected")
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil {
 		log.WithField("path", request.Path).WithError(err).Error("DeleteFile failed")
 		return err
 	}
@@ -242,7 +463,15 @@ func (handler *FileboxMessageHandler) WriteFile(request protocol.WriteFileReques
 	file, ok := handler.fileHandles.Load(request.FileHandle)
 	if !ok {
 		log.WithField("fh", request.FileHandle).Error("Invalid file handle in WriteFile request")
-		return nil, os.ErrInvalid
+		return nil, syscall.EBADF
+	}
+
+	if len(request.Data) > protocol.MaxChunkSize {
+		log.WithFields(log.Fields{
+			"fh":   request.FileHandle,
+			"size": len(request.Data),
+		}).Warn("WriteFile rejected")
+		return nil, fmt.Errorf("write size must be at most %d bytes: %w", protocol.MaxChunkSize, os.ErrInvalid)
 	}
 
 	log.WithFields(log.Fields{
@@ -251,6 +480,12 @@ func (handler *FileboxMessageHandler) WriteFile(request protocol.WriteFileReques
 		"size":   len(request.Data),
 	}).Tracef("Writing file %s", file.(*os.File).Name())
 
+	end := request.Offset + int64(len(request.Data))
+	if err := handler.checkMandatoryLocks(file.(*os.File).Stat, request.Offset, end, true); err != nil {
+		log.WithField("fh", request.FileHandle).WithError(err).Warn("WriteFile rejected")
+		return nil, err
+	}
+
 	bytesWritten, err := file.(*os.File).WriteAt(request.Data, request.Offset)
 	if err != nil && err != io.EOF {
 		log.WithFields(log.Fields{
@@ -266,12 +501,440 @@ func (handler *FileboxMessageHandler) WriteFile(request protocol.WriteFileReques
 	}, nil
 }
 
+func (handler *FileboxMessageHandler) CreateSymlink(request protocol.CreateSymlinkRequest) error {
+	log.WithField("target", request.Target).Tracef("Creating symbolic link %s", request.Path)
+
+	if err := handler.confineLinkTarget(request.Path, request.Target); err != nil {
+		log.WithFields(log.Fields{
+			"path":   request.Path,
+			"target": request.Target,
+		}).WithError(err).Warn("CreateSymlink rejected")
+		return err
+	}
+
+	linkPath, err := handler.resolveEntry(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("CreateSymlink rejected")
+		return err
+	}
+
+	if err := os.Symlink(filepath.FromSlash(request.Target), linkPath); err != nil {
+		log.WithFields(log.Fields{
+			"path":   request.Path,
+			"target": request.Target,
+		}).WithError(err).Error("CreateSymlink failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) ReadSymlink(request protocol.ReadSymlinkRequest) (*protocol.ReadSymlinkResponse, error) {
+	log.Tracef("Reading symbolic link %s", request.Path)
+
+	linkPath, err := handler.resolveEntry(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ReadSymlink rejected")
+		return nil, err
+	}
+
+	target, err := os.Readlink(linkPath)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ReadSymlink failed")
+		return nil, err
+	}
+
+	return &protocol.ReadSymlinkResponse{
+		Target: filepath.ToSlash(target),
+	}, nil
+}
+
+func (handler *FileboxMessageHandler) CreateHardLink(request protocol.CreateHardLinkRequest) error {
+	log.Tracef("Linking %s to %s", request.NewPath, request.OldPath)
+
+	oldPath, err := handler.resolveEntry(request.OldPath)
+	if err != nil {
+		log.WithField("path", request.OldPath).WithError(err).Warn("CreateHardLink rejected")
+		return err
+	}
+
+	newPath, err := handler.resolveEntry(request.NewPath)
+	if err != nil {
+		log.WithField("path", request.NewPath).WithError(err).Warn("CreateHardLink rejected")
+		return err
+	}
+
+	if err := os.Link(oldPath, newPath); err != nil {
+		log.WithFields(log.Fields{
+			"old_path": request.OldPath,
+			"new_path": request.NewPath,
+		}).WithError(err).Error("CreateHardLink failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) GetExtendedAttribute(request protocol.GetExtendedAttributeRequest) (*protocol.GetExtendedAttributeResponse, error) {
+	log.WithField("name", request.Name).Tracef("Getting extended attribute of %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("GetExtendedAttribute rejected")
+		return nil, err
+	}
+
+	value, err := getXattr(filePath, request.Name)
+	if err != nil {
+		// Missing attributes are common, e.g when tools look for ACLs.
+		log.WithFields(log.Fields{
+			"path": request.Path,
+			"name": request.Name,
+		}).WithError(err).Trace("GetExtendedAttribute failed")
+		return nil, err
+	}
+
+	return &protocol.GetExtendedAttributeResponse{
+		Value: value,
+	}, nil
+}
+
+func (handler *FileboxMessageHandler) ListExtendedAttributes(request protocol.ListExtendedAttributesRequest) (*protocol.ListExtendedAttributesResponse, error) {
+	log.Tracef("Listing extended attributes of %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ListExtendedAttributes rejected")
+		return nil, err
+	}
+
+	names, err := listXattrs(filePath)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ListExtendedAttributes failed")
+		return nil, err
+	}
+
+	return &protocol.ListExtendedAttributesResponse{
+		Names: names,
+	}, nil
+}
+
+// ErrXattrNamespace is returned for extended attributes outside the user
+// namespace, unless the server allows every namespace.
+var ErrXattrNamespace = fmt.Errorf("extended attribute namespace not allowed: %w", os.ErrPermission)
+
+// checkXattrNamespace only allows clients to change attributes in the "user."
+// namespace. The other namespaces, such as "trusted." and "security.", control
+// the server itself.
+func (handler *FileboxMessageHandler) checkXattrNamespace(name string) error {
+	if handler.AllXattrNamespaces || strings.HasPrefix(name, "user.") {
+		return nil
+	}
+
+	return ErrXattrNamespace
+}
+
+func (handler *FileboxMessageHandler) SetExtendedAttribute(request protocol.SetExtendedAttributeRequest) error {
+	log.WithFields(log.Fields{
+		"name":  request.Name,
+		"size":  len(request.Value),
+		"flags": request.Flags,
+	}).Tracef("Setting extended attribute of %s", request.Path)
+
+	if request.Flags&^(protocol.XattrCreate|protocol.XattrReplace) != 0 {
+		log.WithField("flags", request.Flags).Warn("SetExtendedAttribute rejected")
+		return fmt.Errorf("invalid extended attribute flags %d: %w", request.Flags, os.ErrInvalid)
+	}
+
+	if err := handler.checkXattrNamespace(request.Name); err != nil {
+		log.WithField("name", request.Name).Warn("SetExtendedAttribute rejected")
+		return err
+	}
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("SetExtendedAttribute rejected")
+		return err
+	}
+
+	if err := setXattr(filePath, request.Name, request.Value, request.Flags); err != nil {
+		log.WithFields(log.Fields{
+			"path": request.Path,
+			"name": request.Name,
+		}).WithError(err).Error("SetExtendedAttribute failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) RemoveExtendedAttribute(request protocol.RemoveExtendedAttributeRequest) error {
+	log.WithField("name", request.Name).Tracef("Removing extended attribute of %s", request.Path)
+
+	if err := handler.checkXattrNamespace(request.Name); err != nil {
+		log.WithField("name", request.Name).Warn("RemoveExtendedAttribute rejected")
+		return err
+	}
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("RemoveExtendedAttribute rejected")
+		return err
+	}
+
+	if err := removeXattr(filePath, request.Name); err != nil {
+		log.WithFields(log.Fields{
+			"path": request.Path,
+			"name": request.Name,
+		}).WithError(err).Error("RemoveExtendedAttribute failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) ChangeMode(request protocol.ChangeModeRequest) error {
+	log.WithField("mode", request.Mode).Tracef("Changing mode of %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ChangeMode rejected")
+		return err
+	}
+
+	if err := os.Chmod(filePath, unixFileMode(request.Mode, handler.AllowSetid)); err != nil {
+		log.WithFields(log.Fields{
+			"path": request.Path,
+			"mode": request.Mode,
+		}).WithError(err).Error("ChangeMode failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) ChangeOwner(request protocol.ChangeOwnerRequest) error {
+	log.WithFields(log.Fields{
+		"uid": request.Uid,
+		"gid": request.Gid,
+	}).Tracef("Changing owner of %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ChangeOwner rejected")
+		return err
+	}
+
+	if err := checkOwner(request.Uid, request.Gid); err != nil {
+		log.WithFields(log.Fields{
+			"uid": request.Uid,
+			"gid": request.Gid,
+		}).WithError(err).Warn("ChangeOwner rejected")
+		return err
+	}
+
+	if err := os.Chown(filePath, request.Uid, request.Gid); err != nil {
+		log.WithFields(log.Fields{
+			"path": request.Path,
+			"uid":  request.Uid,
+			"gid":  request.Gid,
+		}).WithError(err).Error("ChangeOwner failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) ChangeTimes(request protocol.ChangeTimesRequest) error {
+	log.WithFields(log.Fields{
+		"atime": request.AccessTime,
+		"mtime": request.ModTime,
+	}).Tracef("Changing times of %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("ChangeTimes rejected")
+		return err
+	}
+
+	accessTime, modTime := request.AccessTime, request.ModTime
+	if accessTime.IsZero() || modTime.IsZero() {
+		fileInfo, err := os.Stat(filePath)
+		if err != nil {
+			log.WithField("path", request.Path).WithError(err).Warn("os.Stat() failed")
+			return err
+		}
+
+		current := convertFileInfo(fileInfo)
+		if accessTime.IsZero() {
+			accessTime = current.AccessTime
+		}
+		if modTime.IsZero() {
+			modTime = current.ModTime
+		}
+	}
+
+	if err := os.Chtimes(filePath, accessTime, modTime); err != nil {
+		log.WithFields(log.Fields{
+			"path":  request.Path,
+			"atime": accessTime,
+			"mtime": modTime,
+		}).WithError(err).Error("ChangeTimes failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) FsyncFile(request protocol.FsyncFileRequest) error {
+	file, ok := handler.fileHandles.Load(request.FileHandle)
+	if !ok {
+		log.WithField("fh", request.FileHandle).Error("Invalid file handle in FsyncFile request")
+		return syscall.EBADF
+	}
+
+	log.WithField("fh", request.FileHandle).Tracef("Syncing file %s", file.(*os.File).Name())
+
+	if err := file.(*os.File).Sync(); err != nil {
+		log.WithField("fh", request.FileHandle).WithError(err).Error("file.Sync failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) FsyncDirectory(request protocol.FsyncDirectoryRequest) error {
+	log.Tracef("Syncing directory %s", request.Path)
+
+	directoryPath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("FsyncDirectory rejected")
+		return err
+	}
+
+	if err := syncDirectory(directoryPath); err != nil {
+		log.WithField("path", request.Path).WithError(err).Error("FsyncDirectory failed")
+		return err
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) Statfs(request protocol.StatfsRequest) (*protocol.StatfsResponse, error) {
+	log.Tracef("Statfs %s", request.Path)
+
+	filePath, err := handler.resolvePath(request.Path)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Warn("Statfs rejected")
+		return nil, err
+	}
+
+	response, err := diskUsage(filePath)
+	if err != nil {
+		log.WithField("path", request.Path).WithError(err).Error("Statfs failed")
+		return nil, err
+	}
+
+	if handler.Quota > 0 && response.BlockSize > 0 {
+		capBlocks(response, handler.Quota/response.BlockSize)
+
+		if handler.Quotas != nil {
+			free := int64(handler.Quota) - handler.Quotas.TotalUsage()
+			if free < 0 {
+				free = 0
+			}
+			capFreeBlocks(response, uint64(free)/response.BlockSize)
+		}
+	}
+
+	if quotaPath, ok := handler.quotaPath(filePath); ok {
+		handler.Quotas.CapStatfs(response, quotaPath, handler.Username)
+	}
+
+	return response, nil
+}
+
+// capBlocks reports a file system of at most blocks blocks. The free space
+// is capped too, so it's never larger than the file system.
+func capBlocks(response *protocol.StatfsResponse, blocks uint64) {
+	if response.Blocks > blocks {
+		response.Blocks = blocks
+	}
+	if response.BlocksFree > response.Blocks {
+		response.BlocksFree = response.Blocks
+	}
+	if response.BlocksAvailable > response.Blocks {
+		response.BlocksAvailable = response.Blocks
+	}
+}
+
+// capFreeBlocks reports at most blocks free blocks.
+func capFreeBlocks(response *protocol.StatfsResponse, blocks uint64) {
+	if response.BlocksFree > blocks {
+		response.BlocksFree = blocks
+	}
+	if response.BlocksAvailable > blocks {
+		response.BlocksAvailable = blocks
+	}
+}
+
 func convertFileInfo(file os.FileInfo) protocol.FileInfo {
-	return protocol.FileInfo{
+	fileInfo := protocol.FileInfo{
 		Name:    file.Name(),
 		Size:    file.Size(),
 		Mode:    file.Mode(),
 		ModTime: file.ModTime(),
 		IsDir:   file.IsDir(),
 	}
+
+	statAttributes(file, &fileInfo)
+	return fileInfo
+}
+
+// ErrForeignOwner is returned for requests that give a file to a user or a
+// group that the server doesn't run as.
+var ErrForeignOwner = fmt.Errorf("owner is not the server's user or group: %w", os.ErrPermission)
+
+// checkOwner only allows files to be given to the user and the groups of the
+// server's process, which every client acts as. -1 leaves the owner as is.
+func checkOwner(uid int, gid int) error {
+	if uid != -1 && uid != os.Getuid() {
+		return ErrForeignOwner
+	}
+
+	if gid == -1 || gid == os.Getgid() {
+		return nil
+	}
+
+	groups, err := os.Getgroups()
+	if err != nil {
+		return ErrForeignOwner
+	}
+
+	for _, group := range groups {
+		if group == gid {
+			return nil
+		}
+	}
+
+	return ErrForeignOwner
+}
+
+// unixFileMode converts Unix permission bits to an os.FileMode. The setuid
+// and setgid bits are dropped unless setid is true, since files on the server
+// would run with the identity of the server's user.
+func unixFileMode(mode uint32, setid bool) os.FileMode {
+	fileMode := os.FileMode(mode & 0777)
+
+	if mode&04000 != 0 && setid {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&02000 != 0 && setid {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&01000 != 0 {
+		fileMode |= os.ModeSticky
+	}
+
+	return fileMode
 }
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// Notifier broadcasts the changes that a client makes in the shared directory
+// to every other connected client.
+type Notifier struct {
+	mutex   sync.RWMutex
+	clients map[*FileboxMessageHandler]*clientConnection
+}
+
+func NewNotifier() *Notifier {
+	return &Notifier{clients: make(map[*FileboxMessageHandler]*clientConnection)}
+}
+
+// subscribe starts sending notifications to a connection.
+func (notifier *Notifier) subscribe(handler *FileboxMessageHandler, client *clientConnection) {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	notifier.clients[handler] = client
+}
+
+// unsubscribe stops sending notifications to a connection.
+func (notifier *Notifier) unsubscribe(handler *FileboxMessageHandler) {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	delete(notifier.clients, handler)
+}
+
+// Notify sends a notification to every client except the one that made the
+// change. Clients only hear about the paths that their policy lets them read.
+func (notifier *Notifier) Notify(source *FileboxMessageHandler, notification protocol.ChangeNotification) {
+	notifier.mutex.RLock()
+	defer notifier.mutex.RUnlock()
+
+	for handler, client := range notifier.clients {
+		if handler == source {
+			continue
+		}
+
+		visible, ok := handler.visibleChange(notification)
+		if !ok {
+			continue
+		}
+
+		message := &protocol.Message{
+			IsResponse: false,
+			Data:       &visible,
+		}
+
+		// Don't let a slow client delay the request that made the change.
+		// A client that misses a notification might use stale cache entries
+		// until they expire.
+		if !client.trySend(message) {
+			log.WithFields(log.Fields{
+				"username": handler.Username,
+				"path":     notification.Path,
+			}).Warn("Send queue is full, dropping change notification")
+		}
+	}
+}
+
+// visibleChange returns the part of a change that the connection's policy
+// lets it read. A rename that only one side of is readable is seen as the
+// deletion of the old path, or as the creation of the new one.
+func (handler *FileboxMessageHandler) visibleChange(notification protocol.ChangeNotification) (protocol.ChangeNotification, bool) {
+	if handler.Policy == nil {
+		return notification, true
+	}
+
+	canRead := func(requestPath string) bool {
+		return handler.Policy.Check(handler.Username, ActionRead, requestPath) == nil
+	}
+
+	if notification.Type != protocol.ChangeRenamed {
+		return notification, canRead(notification.Path)
+	}
+
+	switch oldVisible, newVisible := canRead(notification.Path), canRead(notification.NewPath); {
+	case oldVisible && newVisible:
+		return notification, true
+	case oldVisible:
+		return protocol.ChangeNotification{Type: protocol.ChangeDeleted, Path: notification.Path}, true
+	case newVisible:
+		return protocol.ChangeNotification{Type: protocol.ChangeCreated, Path: notification.NewPath}, true
+	default:
+		return notification, false
+	}
+}
+
+// notifyWritten notifies the other clients about the writes to a file handle,
+// if there were any since the last notification.
+func (handler *FileboxMessageHandler) notifyWritten(fileHandle uint64) {
+	filePath, ok := handler.writtenFiles.Load(fileHandle)
+	if !ok {
+		return
+	}
+
+	handler.writtenFiles.Delete(fileHandle)
+	handler.Notifier.Notify(handler, protocol.ChangeNotification{
+		Type: protocol.ChangeWritten,
+		Path: cleanRequestPath(filePath.(string)),
+	})
+}
+
+// notifyChange notifies the other clients about a request that succeeded.
+// Writes are coalesced: a file that is written in many requests is notified
+// about once, when the handle is synced or closed.
+func (handler *FileboxMessageHandler) notifyChange(request interface{}) {
+	if handler.Notifier == nil {
+		return
+	}
+
+	var notification protocol.ChangeNotification
+
+	switch request := request.(type) {
+	case protocol.OpenFileRequest:
+		if request.Flags&(os.O_CREATE|os.O_TRUNC) == 0 {
+			return
+		}
+		notification = protocol.ChangeNotification{Type: protocol.ChangeWritten, Path: request.Path}
+
+	case protocol.CreateFileRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeCreated, Path: request.Path}
+
+	case protocol.CreateDirectoryRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeCreated, Path: request.Path}
+
+	case protocol.RenameRequest:
+		notification = protocol.ChangeNotification{
+			Type:    protocol.ChangeRenamed,
+			Path:    request.OldPath,
+			NewPath: cleanRequestPath(request.NewPath),
+		}
+
+	case protocol.DeleteFileRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeDeleted, Path: request.Path}
+
+	case protocol.DeleteDirectoryRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeDeleted, Path: request.Path}
+
+	case protocol.TruncateRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeWritten, Path: request.Path}
+		if handler.isFileHandle(request.FileHandle) {
+			notification.Path = handler.fileHandlePath(request.FileHandle)
+		}
+
+	case protocol.CreateSymlinkRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeCreated, Path: request.Path}
+
+	case protocol.CreateHardLinkRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeCreated, Path: request.NewPath}
+
+	case protocol.SetExtendedAttributeRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeAttributes, Path: request.Path}
+
+	case protocol.RemoveExtendedAttributeRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeAttributes, Path: request.Path}
+
+	case protocol.ChangeModeRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeAttributes, Path: request.Path}
+
+	case protocol.ChangeOwnerRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeAttributes, Path: request.Path}
+
+	case protocol.ChangeTimesRequest:
+		notification = protocol.ChangeNotification{Type: protocol.ChangeAttributes, Path: request.Path}
+
+	case protocol.WriteFileRequest:
+		if filePath := handler.fileHandlePath(request.FileHandle); filePath != "" {
+			handler.writtenFiles.Store(request.FileHandle, filePath)
+		}
+		return
+
+	case protocol.FsyncFileRequest:
+		handler.notifyWritten(request.FileHandle)
+		return
+
+	case protocol.CloseFileRequest:
+		handler.notifyWritten(request.FileHandle)
+		return
+
+	default:
+		return
+	}
+
+	if notification.Path == "" {
+		return
+	}
+
+	notification.Path = cleanRequestPath(notification.Path)
+	handler.Notifier.Notify(handler, notification)
+}
+
+// fileHandlePath returns the path of an open file, relative to the shared
+// directory. It returns an empty string if the handle isn't valid.
+func (handler *FileboxMessageHandler) fileHandlePath(fileHandle uint64) string {
+	file, ok := handler.fileHandles.Load(fileHandle)
+	if !ok {
+		return ""
+	}
+
+	root, err := handler.root()
+	if err != nil {
+		return ""
+	}
+
+	relative, err := filepath.Rel(root, file.(*os.File).Name())
+	if err != nil {
+		return ""
+	}
+
+	return relative
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxSymlinkDepth is the number of symbolic links that resolveMissing follows
+// before giving up, like the kernel's limit for path lookups.
+const maxSymlinkDepth = 40
+
+// ErrPathOutsideRoot is returned when a request path resolves to a location
+// outside of the shared directory, either through ".." elements or through
+// a symbolic link.
+var ErrPathOutsideRoot = &pathOutsideRootError{}
+
+type pathOutsideRootError struct{}
+
+func (*pathOutsideRootError) Error() string {
+	return "path is outside of the shared directory"
+}
+
+// Is makes errors.Is(err, os.ErrPermission) hold for escape attempts.
+func (*pathOutsideRootError) Is(target error) bool {
+	return target == os.ErrPermission
+}
+
+// ErrNulInPath is returned for request paths with NUL bytes, which system
+// calls would cut short.
+var ErrNulInPath = fmt.Errorf("path contains a NUL byte: %w", os.ErrPermission)
+
+// root returns the canonical absolute path of the shared directory.
+func (handler *FileboxMessageHandler) root() (string, error) {
+	handler.rootOnce.Do(func() {
+		root, err := filepath.Abs(handler.BasePath)
+		if err == nil {
+			root, err = filepath.EvalSymlinks(root)
+		}
+		handler.rootPath, handler.rootErr = root, err
+	})
+
+	return handler.rootPath, handler.rootErr
+}
+
+// resolvePath converts a client path to a path on the server's disk,
+// following symbolic links. It fails with ErrPathOutsideRoot if the result
+// isn't inside the shared directory.
+func (handler *FileboxMessageHandler) resolvePath(requestPath string) (string, error) {
+	parent, name, err := handler.resolveParent(requestPath)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return parent, nil
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Join(parent, name))
+	if os.IsNotExist(err) {
+		// The file doesn't exist yet (e.g when creating it), or it's a
+		// dangling symbolic link that O_CREAT would follow.
+		return handler.resolveMissing(requestPath, filepath.Join(parent, name), 0)
+	} else if err != nil {
+		return "", err
+	}
+
+	return handler.confine(requestPath, resolved)
+}
+
+// resolveMissing resolves a path that doesn't exist on the disk. The parent
+// of entry must already be resolved.
+func (handler *FileboxMessageHandler) resolveMissing(requestPath string, entry string, depth int) (string, error) {
+	info, err := os.Lstat(entry)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return handler.confine(requestPath, entry)
+	}
+
+	if depth >= maxSymlinkDepth {
+		return "", &os.PathError{Op: "resolve", Path: requestPath, Err: syscall.ELOOP}
+	}
+
+	target, err := os.Readlink(entry)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(entry), target)
+	}
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+	if os.IsNotExist(err) {
+		return handler.confine(requestPath, filepath.Clean(target))
+	} else if err != nil {
+		return "", err
+	}
+
+	if _, err := handler.confine(requestPath, parent); err != nil {
+		return "", err
+	}
+
+	return handler.resolveMissing(requestPath, filepath.Join(parent, filepath.Base(target)), depth+1)
+}
+
+// resolveParent resolves every element of a client path except the last one,
+// which is returned as is. This is used by operations that act on a directory
+// entry rather than on the file it points to, such as Rename and DeleteFile.
+// For the shared directory itself, name is empty.
+func (handler *FileboxMessageHandler) resolveParent(requestPath string) (parent string, name string, err error) {
+	root, err := handler.root()
+	if err != nil {
+		return "", "", err
+	}
+
+	if strings.ContainsRune(requestPath, 0) {
+		return "", "", &os.PathError{Op: "resolve", Path: requestPath, Err: ErrNulInPath}
+	}
+
+	// Request paths are relative to the shared directory, even if they
+	// start with a slash.
+	cleanPath := path.Clean(strings.TrimLeft(filepath.ToSlash(requestPath), "/"))
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", "", &os.PathError{Op: "resolve", Path: requestPath, Err: ErrPathOutsideRoot}
+	}
+
+	if cleanPath == "." {
+		return root, "", nil
+	}
+
+	dir, name := path.Split(cleanPath)
+	if strings.ContainsRune(name, filepath.Separator) || filepath.VolumeName(name) != "" {
+		return "", "", &os.PathError{Op: "resolve", Path: requestPath, Err: ErrPathOutsideRoot}
+	}
+
+	parent, err = resolveExisting(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		return "", "", err
+	}
+
+	if parent, err = handler.confine(requestPath, parent); err != nil {
+		return "", "", err
+	}
+
+	return parent, name, nil
+}
+
+// resolveExisting follows the symbolic links of a path. If the path doesn't
+// exist, the links of its longest existing prefix are followed and the rest
+// is joined as is, so a missing directory under a link that leads outside of
+// the shared directory is still confined. The actual operation then fails
+// with a meaningful error.
+func resolveExisting(filePath string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(filePath)
+	if !os.IsNotExist(err) {
+		return resolved, err
+	}
+
+	parent := filepath.Dir(filePath)
+	if parent == filePath {
+		return filePath, nil
+	}
+
+	resolved, err = resolveExisting(parent)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(resolved, filepath.Base(filePath)), nil
+}
+
+// resolveEntry is like resolveParent, but joins the parent and the name.
+// The shared directory itself can't be used as an entry, so it can't be
+// renamed or deleted.
+func (handler *FileboxMessageHandler) resolveEntry(requestPath string) (string, error) {
+	parent, name, err := handler.resolveParent(requestPath)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return "", &os.PathError{Op: "resolve", Path: requestPath, Err: os.ErrPermission}
+	}
+
+	return filepath.Join(parent, name), nil
+}
+
+// confine makes sure that an already resolved path is inside the shared directory.
+func (handler *FileboxMessageHandler) confine(requestPath string, resolved string) (string, error) {
+	root, err := handler.root()
+	if err != nil {
+		return "", err
+	}
+
+	relative, err := filepath.Rel(root, resolved)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", &os.PathError{Op: "resolve", Path: requestPath, Err: ErrPathOutsideRoot}
+	}
+
+	return resolved, nil
+}
+
+// confineLinkTarget makes sure that a symbolic link at requestPath that points
+// to target doesn't lead outside of the shared directory. Targets are kept
+// as they are on the disk, so clients can follow them, and they must be
+// relative: an absolute path on the server means nothing to a client.
+//
+// The target is followed from the directory that the link is created in on
+// the disk, like the kernel would follow it, rather than from requestPath,
+// whose elements may be symbolic links themselves. It must stay inside the
+// shared directory at every step.
+func (handler *FileboxMessageHandler) confineLinkTarget(requestPath string, target string) error {
+	if strings.ContainsRune(target, 0) {
+		return &os.PathError{Op: "symlink", Path: requestPath, Err: ErrNulInPath}
+	}
+
+	slashTarget := filepath.ToSlash(target)
+	if target == "" || path.IsAbs(slashTarget) || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return &os.PathError{Op: "symlink", Path: requestPath, Err: ErrPathOutsideRoot}
+	}
+
+	resolved, name, err := handler.resolveParent(requestPath)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		return &os.PathError{Op: "symlink", Path: requestPath, Err: os.ErrPermission}
+	}
+
+	for _, element := range strings.Split(slashTarget, "/") {
+		switch element {
+		case "", ".":
+			continue
+
+		case "..":
+			resolved = filepath.Dir(resolved)
+
+		default:
+			next := filepath.Join(resolved, element)
+			resolved, err = filepath.EvalSymlinks(next)
+			if os.IsNotExist(err) {
+				resolved, err = handler.resolveMissing(requestPath, next, 0)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := handler.confine(requestPath, resolved); err != nil {
+			return &os.PathError{Op: "symlink", Path: requestPath, Err: ErrPathOutsideRoot}
+		}
+	}
+
+	return nil
+}
+
+// cleanRequestPath returns the canonical form of a path in a request, as
+// clients see it.
+func cleanRequestPath(requestPath string) string {
+	return path.Clean("/" + filepath.ToSlash(requestPath))
+}
+
+// isUnder returns true if requestPath is directoryPath or a path under it.
+// Both are relative to the shared directory.
+func isUnder(requestPath string, directoryPath string) bool {
+	return requestPath == directoryPath || directoryPath == "/" || strings.HasPrefix(requestPath, directoryPath+"/")
+}
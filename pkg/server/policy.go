@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// ErrAccessDenied is returned for requests that the access policy denies.
+var ErrAccessDenied = fmt.Errorf("access denied by policy: %w", os.ErrPermission)
+
+// Action is a kind of operation that a policy rule can deny.
+type Action string
+
+const (
+	ActionRead   Action = "read"   // open files for reading, list directories and get attributes
+	ActionWrite  Action = "write"  // open files for writing, truncate them and change their attributes
+	ActionCreate Action = "create" // create files and directories
+	ActionDelete Action = "delete" // delete files and directories
+	ActionRename Action = "rename" // rename or move files and directories
+)
+
+// readOnlyActions are the actions that a ReadOnly rule denies.
+var readOnlyActions = []Action{ActionWrite, ActionCreate, ActionDelete, ActionRename}
+
+// treeActions are the actions that also act on everything under a directory:
+// deleting a directory deletes what's in it, and renaming it moves what's in
+// it.
+var treeActions = []Action{ActionDelete, ActionRename}
+
+// PolicyRule denies actions to a user under a path in the shared directory.
+type PolicyRule struct {
+	User     string   `json:"user"`     // "*" matches every user
+	Path     string   `json:"path"`     // the rule applies to this path and everything under it
+	ReadOnly bool     `json:"readOnly"` // deny every action except read
+	Deny     []Action `json:"deny"`
+}
+
+// Policy is a list of access rules. Every action is allowed, unless a rule
+// denies it.
+//
+// A policy file is a JSON document such as:
+//
+//	{"rules": [
+//	  {"user": "alice", "path": "/reports", "readOnly": true},
+//	  {"user": "*", "path": "/archive", "deny": ["delete", "rename"]}
+//	]}
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// LoadPolicy reads a policy file.
+func LoadPolicy(policyFile string) (*Policy, error) {
+	content, err := ioutil.ReadFile(policyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	if err := json.Unmarshal(content, policy); err != nil {
+		return nil, fmt.Errorf("%s: %v", policyFile, err)
+	}
+
+	for i, rule := range policy.Rules {
+		if rule.User == "" || rule.Path == "" {
+			return nil, fmt.Errorf("%s: rule %d must have a user and a path", policyFile, i)
+		}
+
+		for _, action := range rule.Deny {
+			switch action {
+			case ActionRead, ActionWrite, ActionCreate, ActionDelete, ActionRename:
+			default:
+				return nil, fmt.Errorf("%s: rule %d has an unknown action %q", policyFile, i, action)
+			}
+		}
+
+		policy.Rules[i].Path = cleanRequestPath(rule.Path)
+	}
+
+	return policy, nil
+}
+
+// HasUserRules returns true if some rules apply to specific users. Since only
+// per-user tokens prove a username, these rules require a tokens file.
+func (policy *Policy) HasUserRules() bool {
+	for _, rule := range policy.Rules {
+		if rule.User != "*" && rule.User != AnonymousUser {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check returns ErrAccessDenied if the policy denies the action to the user.
+// requestPath is relative to the shared directory. Deleting and renaming are
+// also denied if a rule denies them anywhere under requestPath.
+func (policy *Policy) Check(username string, action Action, requestPath string) error {
+	requestPath = cleanRequestPath(requestPath)
+	tree := action.actsOnTree()
+
+	for _, rule := range policy.Rules {
+		if rule.User != "*" && rule.User != username {
+			continue
+		}
+
+		if !isUnder(requestPath, rule.Path) && !(tree && isUnder(rule.Path, requestPath)) {
+			continue
+		}
+
+		if rule.denies(action) {
+			return ErrAccessDenied
+		}
+	}
+
+	return nil
+}
+
+func (action Action) actsOnTree() bool {
+	for _, treeAction := range treeActions {
+		if action == treeAction {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule *PolicyRule) denies(action Action) bool {
+	actions := rule.Deny
+	if rule.ReadOnly {
+		actions = append(actions[:len(actions):len(actions)], readOnlyActions...)
+	}
+
+	for _, denied := range actions {
+		if denied == action {
+			return true
+		}
+	}
+
+	return false
+}
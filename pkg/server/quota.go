@@ -0,0 +1,865 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrQuotaExceeded is returned for requests that would use more storage than
+// a quota allows.
+var ErrQuotaExceeded = fmt.Errorf("storage quota exceeded: %w", syscall.EDQUOT)
+
+// ownersSaveInterval is how often the owners of files are saved, if they changed.
+const ownersSaveInterval = 5 * time.Second
+
+// QuotaRule limits the storage of a user, or of a directory and everything
+// under it.
+type QuotaRule struct {
+	User  string `json:"user"`  // limits the files that the user created
+	Path  string `json:"path"`  // limits the files under this directory
+	Limit int64  `json:"limit"` // in bytes
+}
+
+// QuotaConfig is a list of storage quotas. Users that no quota applies to
+// can use as much storage as they want.
+//
+// A quotas file is a JSON document such as:
+//
+//	{"quotas": [
+//	  {"user": "alice", "limit": 10737418240},
+//	  {"path": "/videos", "limit": 107374182400}
+//	],
+//	 "admins": ["bob"],
+//	 "ownersFile": "/var/lib/filebox/owners.json"}
+//
+// Admins can see the usage of every quota. The owners file keeps the users
+// that created the files, so user quotas survive a restart of the server.
+type QuotaConfig struct {
+	Quotas     []QuotaRule `json:"quotas"`
+	Admins     []string    `json:"admins"`
+	OwnersFile string      `json:"ownersFile"`
+}
+
+// LoadQuotaConfig reads a quotas file.
+func LoadQuotaConfig(quotasFile string) (*QuotaConfig, error) {
+	content, err := ioutil.ReadFile(quotasFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &QuotaConfig{}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("%s: %v", quotasFile, err)
+	}
+
+	for i, rule := range config.Quotas {
+		if (rule.User == "") == (rule.Path == "") {
+			return nil, fmt.Errorf("%s: quota %d must have either a user or a path", quotasFile, i)
+		}
+
+		if rule.Limit < 0 {
+			return nil, fmt.Errorf("%s: quota %d has a negative limit", quotasFile, i)
+		}
+
+		if rule.Path != "" {
+			config.Quotas[i].Path = cleanRequestPath(rule.Path)
+		}
+	}
+
+	return config, nil
+}
+
+// HasUserRules returns true if some quotas or admins are specific users. Since
+// only per-user tokens prove a username, these require a tokens file.
+func (config *QuotaConfig) HasUserRules() bool {
+	for _, rule := range config.Quotas {
+		if rule.User != "" && rule.User != AnonymousUser {
+			return true
+		}
+	}
+
+	for _, admin := range config.Admins {
+		if admin != AnonymousUser {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (config *QuotaConfig) isAdmin(username string) bool {
+	for _, admin := range config.Admins {
+		if admin == username {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applies returns true if the rule limits a file. requestPath is relative to
+// the shared directory, and owner is the user that created the file.
+func (rule *QuotaRule) applies(requestPath string, owner string) bool {
+	if rule.User != "" {
+		return rule.User == owner
+	}
+
+	return isUnder(requestPath, rule.Path)
+}
+
+// QuotaManager keeps the storage that every quota uses, for all the
+// connections. The sizes of the files in the shared directory are counted
+// when the server starts, and are updated by the requests of clients, so
+// changes that are made directly on the server's disk are only counted after
+// a restart. Hard links are counted once for every name, and writes through
+// a file handle only update the name that it was opened with.
+//
+// Requests are checked before they are handled, so writes that are handled
+// at the same time can together exceed a quota by a little.
+type QuotaManager struct {
+	config *QuotaConfig
+
+	mutex   sync.Mutex
+	files   map[string]*quotaFile // by path relative to the shared directory
+	handles map[*QuotaHandle]bool // the open file handles, for renames
+	usage   []int64               // the usage of every rule in config.Quotas
+	total   int64                 // the size of all the files
+	dirty   bool                  // the owners changed since they were saved
+}
+
+type quotaFile struct {
+	size  int64
+	owner string // the user that created the file, or "" if it's unknown
+}
+
+// QuotaHandle follows the path of an open file, so writes through a file
+// handle are counted for the file's current path after it's renamed.
+type QuotaHandle struct {
+	path string // "" once the file is deleted or replaced
+}
+
+// NewQuotaManager counts the storage that the files under basePath use.
+func NewQuotaManager(basePath string, config *QuotaConfig) (*QuotaManager, error) {
+	root, err := filepath.Abs(basePath)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := loadOwners(config.OwnersFile)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &QuotaManager{
+		config:  config,
+		files:   make(map[string]*quotaFile),
+		handles: make(map[*QuotaHandle]bool),
+		usage:   make([]int64, len(config.Quotas)),
+	}
+
+	err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.WithField("path", filePath).WithError(err).Warn("Can't count the storage of a file")
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relative, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+
+		requestPath := cleanRequestPath(relative)
+		manager.setSize(requestPath, info.Size(), owners[requestPath])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Owners of files that were deleted while the server was down are dropped
+	// on the next save.
+	manager.dirty = len(owners) > 0
+
+	if config.OwnersFile != "" {
+		go manager.saveOwnersPeriodically()
+	}
+
+	return manager, nil
+}
+
+// add adds delta bytes to the usage of the rules that apply to a file, and to
+// the total.
+// manager.mutex must be locked.
+func (manager *QuotaManager) add(requestPath string, file *quotaFile, delta int64) {
+	manager.total += delta
+
+	for i := range manager.config.Quotas {
+		if manager.config.Quotas[i].applies(requestPath, file.owner) {
+			manager.usage[i] += delta
+		}
+	}
+}
+
+// setSize updates the size of a file. If the file is new, it's owned by
+// creator. manager.mutex must be locked.
+func (manager *QuotaManager) setSize(requestPath string, size int64, creator string) {
+	file, ok := manager.files[requestPath]
+	if !ok {
+		file = &quotaFile{owner: creator}
+		manager.files[requestPath] = file
+		manager.dirty = manager.dirty || creator != ""
+	}
+
+	manager.add(requestPath, file, size-file.size)
+	file.size = size
+}
+
+// remove forgets a file. Writes through the file handles of the file aren't
+// counted anymore. manager.mutex must be locked.
+func (manager *QuotaManager) remove(requestPath string) {
+	for handle := range manager.handles {
+		if handle.path == requestPath {
+			handle.path = ""
+		}
+	}
+
+	file, ok := manager.files[requestPath]
+	if !ok {
+		return
+	}
+
+	manager.add(requestPath, file, -file.size)
+	delete(manager.files, requestPath)
+	manager.dirty = manager.dirty || file.owner != ""
+}
+
+// Update records the size of a file after a request changed it. Files that
+// weren't counted yet are owned by the user that made the request.
+func (manager *QuotaManager) Update(requestPath string, size int64, username string) {
+	if manager == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.setSize(requestPath, size, username)
+}
+
+// Remove forgets a file that was deleted.
+func (manager *QuotaManager) Remove(requestPath string) {
+	if manager == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.remove(requestPath)
+}
+
+// RemoveTree forgets a file, or every file under a directory that was deleted.
+func (manager *QuotaManager) RemoveTree(requestPath string) {
+	if manager == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for filePath := range manager.files {
+		if isUnder(filePath, requestPath) {
+			manager.remove(filePath)
+		}
+	}
+
+	for handle := range manager.handles {
+		if handle.path != "" && isUnder(handle.path, requestPath) {
+			handle.path = ""
+		}
+	}
+}
+
+// Rename moves a file, or every file under a directory, to a new path. Files
+// that are replaced by the rename are forgotten. Files keep their owners, but
+// directory quotas are moved from the old path to the new one.
+func (manager *QuotaManager) Rename(oldPath string, newPath string) {
+	if manager == nil || oldPath == newPath {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	var movedHandles []*QuotaHandle
+	for handle := range manager.handles {
+		if handle.path != "" && isUnder(handle.path, oldPath) {
+			movedHandles = append(movedHandles, handle)
+		}
+	}
+
+	moved := make(map[string]*quotaFile)
+	for requestPath, file := range manager.files {
+		if isUnder(requestPath, oldPath) {
+			moved[newPath+strings.TrimPrefix(requestPath, oldPath)] = file
+			manager.add(requestPath, file, -file.size)
+			delete(manager.files, requestPath)
+		}
+	}
+
+	manager.remove(newPath)
+
+	for requestPath, file := range moved {
+		manager.remove(requestPath)
+		manager.files[requestPath] = file
+		manager.add(requestPath, file, file.size)
+	}
+
+	for _, handle := range movedHandles {
+		handle.path = newPath + strings.TrimPrefix(handle.path, oldPath)
+	}
+
+	manager.dirty = true
+}
+
+// Open starts following the path of a file that a client opened, until the
+// handle is closed.
+func (manager *QuotaManager) Open(requestPath string) *QuotaHandle {
+	if manager == nil {
+		return nil
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	handle := &QuotaHandle{path: requestPath}
+	manager.handles[handle] = true
+	return handle
+}
+
+// Close stops following the path of a file handle.
+func (manager *QuotaManager) Close(handle *QuotaHandle) {
+	if manager == nil || handle == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	delete(manager.handles, handle)
+}
+
+// CheckHandle is like Check, for the current path of a file handle. Files that
+// were deleted don't use storage that a quota counts.
+func (manager *QuotaManager) CheckHandle(handle *QuotaHandle, username string, growth int64) error {
+	if manager == nil || handle == nil {
+		return nil
+	}
+
+	manager.mutex.Lock()
+	requestPath := handle.path
+	manager.mutex.Unlock()
+
+	if requestPath == "" {
+		return nil
+	}
+
+	return manager.Check(requestPath, username, growth)
+}
+
+// UpdateHandle is like Update, for the current path of a file handle.
+func (manager *QuotaManager) UpdateHandle(handle *QuotaHandle, size int64, username string) {
+	if manager == nil || handle == nil {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if handle.path != "" {
+		manager.setSize(handle.path, size, username)
+	}
+}
+
+// Check returns ErrQuotaExceeded if a file can't grow by growth bytes. A file
+// that isn't counted yet is checked as if username owned it. Growth 0 checks
+// whether a file can be created, which fails once a quota is used up.
+func (manager *QuotaManager) Check(requestPath string, username string, growth int64) error {
+	if manager == nil {
+		return nil
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	owner := username
+	if file, ok := manager.files[requestPath]; ok {
+		owner = file.owner
+	}
+
+	for i, rule := range manager.config.Quotas {
+		if !rule.applies(requestPath, owner) {
+			continue
+		}
+
+		if (growth == 0 && manager.usage[i] >= rule.Limit) || manager.usage[i]+growth > rule.Limit {
+			return ErrQuotaExceeded
+		}
+	}
+
+	return nil
+}
+
+// CheckRename returns ErrQuotaExceeded if moving a file, or every file under a
+// directory, to a new path would exceed the quota of a directory that the
+// files aren't in yet. User quotas don't change, since files keep their owners.
+func (manager *QuotaManager) CheckRename(oldPath string, newPath string) error {
+	if manager == nil {
+		return nil
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for i, rule := range manager.config.Quotas {
+		if rule.User != "" {
+			continue
+		}
+
+		var growth int64
+		for requestPath, file := range manager.files {
+			if !isUnder(requestPath, oldPath) {
+				continue
+			}
+
+			movedPath := newPath + strings.TrimPrefix(requestPath, oldPath)
+			if !rule.applies(requestPath, file.owner) && rule.applies(movedPath, file.owner) {
+				growth += file.size
+			}
+		}
+
+		if growth > 0 && manager.usage[i]+growth > rule.Limit {
+			return ErrQuotaExceeded
+		}
+	}
+
+	return nil
+}
+
+// CapStatfs limits the size and the free space of a file system to the quotas
+// that apply to the files that username creates under requestPath.
+func (manager *QuotaManager) CapStatfs(response *protocol.StatfsResponse, requestPath string, username string) {
+	if manager == nil || response.BlockSize == 0 {
+		return
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	for i, rule := range manager.config.Quotas {
+		if !rule.applies(requestPath, username) {
+			continue
+		}
+
+		free := rule.Limit - manager.usage[i]
+		if free < 0 {
+			free = 0
+		}
+
+		capBlocks(response, uint64(rule.Limit)/response.BlockSize)
+		capFreeBlocks(response, uint64(free)/response.BlockSize)
+	}
+}
+
+// TotalUsage returns the storage that all the files in the shared directory use.
+func (manager *QuotaManager) TotalUsage() int64 {
+	if manager == nil {
+		return 0
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	return manager.total
+}
+
+// Usage returns the quotas that apply to a user and their usage: the quotas
+// of the user, and the quotas of directories. Admins get every quota.
+func (manager *QuotaManager) Usage(username string) []protocol.QuotaUsage {
+	if manager == nil {
+		return nil
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	admin := manager.config.isAdmin(username)
+
+	var quotas []protocol.QuotaUsage
+	for i, rule := range manager.config.Quotas {
+		if rule.User != "" && rule.User != username && !admin {
+			continue
+		}
+
+		quotas = append(quotas, protocol.QuotaUsage{
+			User:  rule.User,
+			Path:  rule.Path,
+			Limit: rule.Limit,
+			Usage: manager.usage[i],
+		})
+	}
+
+	return quotas
+}
+
+// loadOwners reads the owners of files that an earlier run of the server
+// saved. A missing file means nothing was saved yet.
+func loadOwners(ownersFile string) (map[string]string, error) {
+	owners := make(map[string]string)
+	if ownersFile == "" {
+		return owners, nil
+	}
+
+	content, err := ioutil.ReadFile(ownersFile)
+	if os.IsNotExist(err) {
+		return owners, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &owners); err != nil {
+		return nil, fmt.Errorf("%s: %v", ownersFile, err)
+	}
+
+	return owners, nil
+}
+
+func (manager *QuotaManager) saveOwnersPeriodically() {
+	for range time.Tick(ownersSaveInterval) {
+		manager.mutex.Lock()
+		if !manager.dirty {
+			manager.mutex.Unlock()
+			continue
+		}
+
+		owners := make(map[string]string)
+		for requestPath, file := range manager.files {
+			if file.owner != "" {
+				owners[requestPath] = file.owner
+			}
+		}
+		manager.dirty = false
+		manager.mutex.Unlock()
+
+		if err := saveOwners(manager.config.OwnersFile, owners); err != nil {
+			log.WithField("path", manager.config.OwnersFile).WithError(err).Error("Saving the owners of files failed")
+
+			manager.mutex.Lock()
+			manager.dirty = true
+			manager.mutex.Unlock()
+		}
+	}
+}
+
+// saveOwners writes the owners file through a temporary file, so a crash
+// doesn't leave it half written.
+func saveOwners(ownersFile string, owners map[string]string) error {
+	content, err := json.Marshal(owners)
+	if err != nil {
+		return err
+	}
+
+	temporaryFile := ownersFile + ".tmp"
+	if err := ioutil.WriteFile(temporaryFile, content, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFile, ownersFile)
+}
+
+// quotaPath converts a resolved path on the server's disk to the path that
+// quotas use, which is relative to the shared directory.
+func (handler *FileboxMessageHandler) quotaPath(resolved string) (string, bool) {
+	root, err := handler.root()
+	if err != nil {
+		return "", false
+	}
+
+	relative, err := filepath.Rel(root, resolved)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return cleanRequestPath(relative), true
+}
+
+// checkQuota returns ErrQuotaExceeded if a request would use more storage
+// than a quota allows: writes and truncates that grow a file, creating a file
+// once a quota is used up, and renames and hard links that move files into a
+// directory with a quota. Requests that will fail anyway, such as ones
+// with an invalid path, are left to fail on their own.
+func (handler *FileboxMessageHandler) checkQuota(request interface{}) error {
+	if handler.Quotas == nil {
+		return nil
+	}
+
+	switch request := request.(type) {
+	case protocol.CreateFileRequest:
+		return handler.checkCreateQuota(request.Path)
+
+	case protocol.OpenFileRequest:
+		if request.Flags&os.O_CREATE != 0 {
+			return handler.checkCreateQuota(request.Path)
+		}
+
+	case protocol.WriteFileRequest:
+		return handler.checkHandleGrowthQuota(request.FileHandle, request.Offset+int64(len(request.Data)))
+
+	case protocol.TruncateRequest:
+		if !handler.isFileHandle(request.FileHandle) {
+			if filePath, err := handler.resolvePath(request.Path); err == nil {
+				return handler.checkGrowthQuota(filePath, request.Size)
+			}
+		} else {
+			return handler.checkHandleGrowthQuota(request.FileHandle, request.Size)
+		}
+
+	case protocol.RenameRequest:
+		return handler.checkRenameQuota(request.OldPath, request.NewPath)
+
+	case protocol.CreateHardLinkRequest:
+		return handler.checkHardLinkQuota(request.OldPath, request.NewPath)
+	}
+
+	return nil
+}
+
+func (handler *FileboxMessageHandler) checkRenameQuota(oldRequestPath string, newRequestPath string) error {
+	oldPath, oldErr := handler.resolveEntry(oldRequestPath)
+	newPath, newErr := handler.resolveEntry(newRequestPath)
+	if oldErr != nil || newErr != nil {
+		return nil
+	}
+
+	oldQuotaPath, oldOk := handler.quotaPath(oldPath)
+	newQuotaPath, newOk := handler.quotaPath(newPath)
+	if !oldOk || !newOk {
+		return nil
+	}
+
+	return handler.Quotas.CheckRename(oldQuotaPath, newQuotaPath)
+}
+
+// checkHardLinkQuota checks whether a new name can be added to a file. Every
+// name is counted, and the new one is owned by the user that creates it.
+func (handler *FileboxMessageHandler) checkHardLinkQuota(oldRequestPath string, newRequestPath string) error {
+	oldPath, oldErr := handler.resolveEntry(oldRequestPath)
+	newPath, newErr := handler.resolveEntry(newRequestPath)
+	if oldErr != nil || newErr != nil {
+		return nil
+	}
+
+	info, err := os.Lstat(oldPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	quotaPath, ok := handler.quotaPath(newPath)
+	if !ok {
+		return nil
+	}
+
+	return handler.Quotas.Check(quotaPath, handler.Username, info.Size())
+}
+
+func (handler *FileboxMessageHandler) checkCreateQuota(requestPath string) error {
+	filePath, err := handler.resolvePath(requestPath)
+	if err != nil {
+		return nil
+	}
+
+	if _, err := os.Stat(filePath); err == nil {
+		return nil
+	}
+
+	quotaPath, ok := handler.quotaPath(filePath)
+	if !ok {
+		return nil
+	}
+
+	return handler.Quotas.Check(quotaPath, handler.Username, 0)
+}
+
+// checkGrowthQuota checks whether the file at filePath can grow to size bytes.
+func (handler *FileboxMessageHandler) checkGrowthQuota(filePath string, size int64) error {
+	info, err := os.Stat(filePath)
+	if err != nil || size <= info.Size() {
+		return nil
+	}
+
+	quotaPath, ok := handler.quotaPath(filePath)
+	if !ok {
+		return nil
+	}
+
+	return handler.Quotas.Check(quotaPath, handler.Username, size-info.Size())
+}
+
+// checkHandleGrowthQuota checks whether an open file can grow to size bytes.
+// The file handle knows the file's path and size even after it's renamed.
+func (handler *FileboxMessageHandler) checkHandleGrowthQuota(fileHandle uint64, size int64) error {
+	file, ok := handler.fileHandles.Load(fileHandle)
+	if !ok {
+		return nil
+	}
+
+	info, err := file.(*os.File).Stat()
+	if err != nil || size <= info.Size() {
+		return nil
+	}
+
+	return handler.Quotas.CheckHandle(handler.quotaHandle(fileHandle), handler.Username, size-info.Size())
+}
+
+// openQuotaHandle starts following the path of a file that was opened, so
+// writes through its file handle are counted for its current path.
+func (handler *FileboxMessageHandler) openQuotaHandle(fileHandle uint64, filePath string) {
+	if handler.Quotas == nil {
+		return
+	}
+
+	if quotaPath, ok := handler.quotaPath(filePath); ok {
+		handler.quotaHandles.Store(fileHandle, handler.Quotas.Open(quotaPath))
+	}
+}
+
+// closeQuotaHandle stops following the path of a file handle.
+func (handler *FileboxMessageHandler) closeQuotaHandle(fileHandle uint64) {
+	if handle := handler.quotaHandle(fileHandle); handle != nil {
+		handler.Quotas.Close(handle)
+		handler.quotaHandles.Delete(fileHandle)
+	}
+}
+
+func (handler *FileboxMessageHandler) quotaHandle(fileHandle uint64) *QuotaHandle {
+	if handle, ok := handler.quotaHandles.Load(fileHandle); ok {
+		return handle.(*QuotaHandle)
+	}
+
+	return nil
+}
+
+// updateUsage updates the usage of quotas after a request succeeded.
+func (handler *FileboxMessageHandler) updateUsage(request interface{}) {
+	if handler.Quotas == nil {
+		return
+	}
+
+	switch request := request.(type) {
+	case protocol.CreateFileRequest:
+		handler.updateFileUsage(handler.resolvePath(request.Path))
+
+	case protocol.OpenFileRequest:
+		if request.Flags&(os.O_CREATE|os.O_TRUNC) != 0 {
+			handler.updateFileUsage(handler.resolvePath(request.Path))
+		}
+
+	case protocol.WriteFileRequest:
+		handler.updateHandleUsage(request.FileHandle)
+
+	case protocol.TruncateRequest:
+		if !handler.isFileHandle(request.FileHandle) {
+			handler.updateFileUsage(handler.resolvePath(request.Path))
+		} else {
+			handler.updateHandleUsage(request.FileHandle)
+		}
+
+	case protocol.CreateHardLinkRequest:
+		handler.updateFileUsage(handler.resolveEntry(request.NewPath))
+
+	case protocol.DeleteFileRequest:
+		if filePath, err := handler.resolveEntry(request.Path); err == nil {
+			if quotaPath, ok := handler.quotaPath(filePath); ok {
+				handler.Quotas.Remove(quotaPath)
+			}
+		}
+
+	case protocol.DeleteDirectoryRequest:
+		if directoryPath, err := handler.resolveEntry(request.Path); err == nil {
+			if quotaPath, ok := handler.quotaPath(directoryPath); ok {
+				handler.Quotas.RemoveTree(quotaPath)
+			}
+		}
+
+	case protocol.RenameRequest:
+		oldPath, oldErr := handler.resolveEntry(request.OldPath)
+		newPath, newErr := handler.resolveEntry(request.NewPath)
+		if oldErr != nil || newErr != nil {
+			return
+		}
+
+		oldQuotaPath, oldOk := handler.quotaPath(oldPath)
+		newQuotaPath, newOk := handler.quotaPath(newPath)
+		if oldOk && newOk {
+			handler.Quotas.Rename(oldQuotaPath, newQuotaPath)
+		}
+	}
+}
+
+// updateFileUsage records the size of a regular file. It takes the result of
+// resolvePath, so a path that can't be resolved is skipped.
+func (handler *FileboxMessageHandler) updateFileUsage(filePath string, err error) {
+	if err != nil {
+		return
+	}
+
+	info, err := os.Lstat(filePath)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+
+	if quotaPath, ok := handler.quotaPath(filePath); ok {
+		handler.Quotas.Update(quotaPath, info.Size(), handler.Username)
+	}
+}
+
+// updateHandleUsage records the size of an open file, for the file's current
+// path.
+func (handler *FileboxMessageHandler) updateHandleUsage(fileHandle uint64) {
+	file, ok := handler.fileHandles.Load(fileHandle)
+	if !ok {
+		return
+	}
+
+	info, err := file.(*os.File).Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+
+	handler.Quotas.UpdateHandle(handler.quotaHandle(fileHandle), info.Size(), handler.Username)
+}
+
+func (handler *FileboxMessageHandler) QuotaUsage(request protocol.QuotaUsageRequest) (*protocol.QuotaUsageResponse, error) {
+	log.WithField("username", handler.Username).Trace("Getting quota usage")
+
+	return &protocol.QuotaUsageResponse{
+		Quotas: handler.Quotas.Usage(handler.Username),
+	}, nil
+}
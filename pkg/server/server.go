@@ -1,67 +1,207 @@
 package server
 
 import (
-	"encoding/gob"
+	"bufio"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/alongubkin/filebox/pkg/protocol"
 	log "github.com/sirupsen/logrus"
 )
 
-func handleMessage(messageHandler *FileboxMessageHandler, encoder *gob.Encoder, message *protocol.Message) {
-	if message.IsResponse {
-		log.Warn("Got a message from a client with IsResponse flag turned on. Ignoring")
-	}
+// Config contains the settings of a Filebox server.
+type Config struct {
+	BasePath string
+	Port     uint16
 
-	response := &protocol.Message{
-		MessageID:  message.MessageID,
-		IsResponse: true,
-	}
+	// GRPCPort is the port of the gRPC service. If it's 0, the service is disabled.
+	GRPCPort uint16
+
+	// TLSConfig enables TLS on the listener. If it's nil, connections use plain TCP.
+	TLSConfig *tls.Config
+
+	// Authenticator checks the credentials of new connections. If it's nil,
+	// every client is accepted.
+	Authenticator *Authenticator
+
+	// Policy restricts the access of users to the shared directory. If it's
+	// nil, every user has full access.
+	Policy *Policy
+
+	// ReadOnly rejects every request that modifies the shared directory.
+	ReadOnly bool
+
+	// Quota caps the size of the shared directory that Statfs reports to
+	// clients, in bytes, and the free space is reported as the cap minus the
+	// storage that the shared directory uses. If it's 0, the size and the
+	// free space of the server's disk are reported.
+	Quota uint64
+
+	// Quotas limits the storage of users and of directories. If it's nil,
+	// storage is only limited by the server's disk.
+	Quotas *QuotaConfig
 
-	var err error
-	var data interface{}
+	// MandatoryLocks makes byte range locks mandatory: reads and writes that
+	// conflict with a lock of another connection fail with ErrorLocked.
+	MandatoryLocks bool
 
-	switch request := message.Data.(type) {
+	// AllXattrNamespaces allows clients to change extended attributes in
+	// every namespace, and not only in "user.".
+	AllXattrNamespaces bool
+
+	// AllowSetid allows clients to set the setuid and setgid bits of files.
+	// Otherwise, they're dropped from ChangeMode requests.
+	AllowSetid bool
+
+	// MaxInFlight is the number of requests from a single connection that
+	// can be handled at the same time. When it's reached, the server stops
+	// reading from the connection. If it's 0, DefaultMaxInFlight is used.
+	MaxInFlight int
+}
+
+const DefaultMaxInFlight = 64
+
+// handshakeTimeout is how long a new connection has to authenticate.
+const handshakeTimeout = 10 * time.Second
+
+func handleRequest(messageHandler *FileboxMessageHandler, request interface{}) (interface{}, error) {
+	switch request := request.(type) {
 	case protocol.OpenFileRequest:
-		data, err = messageHandler.OpenFile(request)
+		return messageHandler.OpenFile(request)
 
 	case protocol.ReadFileRequest:
-		data, err = messageHandler.ReadFile(request)
+		return messageHandler.ReadFile(request)
 
 	case protocol.ReadDirectoryRequest:
-		data, err = messageHandler.ReadDirectory(request)
+		return messageHandler.ReadDirectory(request)
 
 	case protocol.GetFileAttributesRequest:
-		data, err = messageHandler.GetFileAttributes(request)
+		return messageHandler.GetFileAttributes(request)
 
 	case protocol.CloseFileRequest:
-		err = messageHandler.CloseFile(request)
+		return nil, messageHandler.CloseFile(request)
 
 	case protocol.CreateDirectoryRequest:
-		err = messageHandler.CreateDirectory(request)
+		return nil, messageHandler.CreateDirectory(request)
 
 	case protocol.CreateFileRequest:
-		err = messageHandler.CreateFile(request)
+		return nil, messageHandler.CreateFile(request)
 
 	case protocol.RenameRequest:
-		err = messageHandler.Rename(request)
+		return nil, messageHandler.Rename(request)
 
 	case protocol.DeleteDirectoryRequest:
-		err = messageHandler.DeleteDirectory(request)
+		return nil, messageHandler.DeleteDirectory(request)
 
 	case protocol.TruncateRequest:
-		err = messageHandler.Truncate(request)
+		return nil, messageHandler.Truncate(request)
 
 	case protocol.DeleteFileRequest:
-		err = messageHandler.DeleteFile(request)
+		return nil, messageHandler.DeleteFile(request)
 
 	case protocol.WriteFileRequest:
-		data, err = messageHandler.WriteFile(request)
+		return messageHandler.WriteFile(request)
+
+	case protocol.CreateSymlinkRequest:
+		return nil, messageHandler.CreateSymlink(request)
+
+	case protocol.ReadSymlinkRequest:
+		return messageHandler.ReadSymlink(request)
+
+	case protocol.CreateHardLinkRequest:
+		return nil, messageHandler.CreateHardLink(request)
+
+	case protocol.GetExtendedAttributeRequest:
+		return messageHandler.GetExtendedAttribute(request)
+
+	case protocol.ListExtendedAttributesRequest:
+		return messageHandler.ListExtendedAttributes(request)
+
+	case protocol.SetExtendedAttributeRequest:
+		return nil, messageHandler.SetExtendedAttribute(request)
+
+	case protocol.RemoveExtendedAttributeRequest:
+		return nil, messageHandler.RemoveExtendedAttribute(request)
+
+	case protocol.LockRequest:
+		return nil, messageHandler.Lock(request)
+
+	case protocol.UnlockRequest:
+		return nil, messageHandler.Unlock(request)
+
+	case protocol.TestLockRequest:
+		return messageHandler.TestLock(request)
+
+	case protocol.ChangeModeRequest:
+		return nil, messageHandler.ChangeMode(request)
+
+	case protocol.ChangeOwnerRequest:
+		return nil, messageHandler.ChangeOwner(request)
+
+	case protocol.ChangeTimesRequest:
+		return nil, messageHandler.ChangeTimes(request)
+
+	case protocol.FsyncFileRequest:
+		return nil, messageHandler.FsyncFile(request)
+
+	case protocol.FsyncDirectoryRequest:
+		return nil, messageHandler.FsyncDirectory(request)
+
+	case protocol.StatfsRequest:
+		return messageHandler.Statfs(request)
+
+	case protocol.QuotaUsageRequest:
+		return messageHandler.QuotaUsage(request)
 	}
 
+	return nil, nil
+}
+
+// serveRequest checks that a request is allowed, handles it, and notifies
+// the other clients about the change that it made.
+func serveRequest(messageHandler *FileboxMessageHandler, request interface{}) (interface{}, error) {
+	if err := messageHandler.authorize(request); err != nil {
+		log.WithFields(log.Fields{
+			"username": messageHandler.Username,
+			"request":  reflect.TypeOf(request),
+		}).WithError(err).Warn("Request denied")
+		return nil, err
+	}
+
+	if err := messageHandler.checkQuota(request); err != nil {
+		log.WithFields(log.Fields{
+			"username": messageHandler.Username,
+			"request":  reflect.TypeOf(request),
+		}).WithError(err).Warn("Request exceeds quota")
+		return nil, err
+	}
+
+	data, err := handleRequest(messageHandler, request)
+	if err == nil {
+		messageHandler.updateUsage(request)
+		messageHandler.notifyChange(request)
+	}
+
+	return data, err
+}
+
+func handleMessage(messageHandler *FileboxMessageHandler, client *clientConnection, message *protocol.Message) {
+	if message.IsResponse {
+		log.Warn("Got a message from a client with IsResponse flag turned on. Ignoring")
+	}
+
+	response := &protocol.Message{
+		MessageID:  message.MessageID,
+		IsResponse: true,
+	}
+
+	data, err := serveRequest(messageHandler, message.Data)
+
 	// data == nil won't work here because in Go, nil.(interface{}) != nil.(MyCommandResponse)
 	if val := reflect.ValueOf(data); !val.IsValid() || val.IsNil() {
 		response.Data = &protocol.EmptyResponse{}
@@ -69,19 +209,97 @@ func handleMessage(messageHandler *FileboxMessageHandler, encoder *gob.Encoder,
 		response.Data = data
 	}
 
-	response.Success = (err == nil)
-
-	if err := encoder.Encode(response); err != nil {
-		log.WithError(err).Error("encoder.Encode failed")
+	if err != nil {
+		response.ErrorCode = protocol.ErrorCodeOf(err)
+		response.ErrorMessage = err.Error()
 	}
+
+	client.send(response)
 }
 
-func handleConnection(messageHandler *FileboxMessageHandler, connection net.Conn) {
-	encoder := gob.NewEncoder(connection)
-	decoder := gob.NewDecoder(connection)
+func handleConnection(config *Config, notifier *Notifier, locks *LockManager, quotas *QuotaManager, connection net.Conn) {
+	defer connection.Close()
 
 	log.WithField("address", connection.RemoteAddr()).Info("Handling new connection")
 
+	connection.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	reader := bufio.NewReader(connection)
+	encoding, err := protocol.ReadPreface(reader)
+	if err != nil {
+		log.WithField("address", connection.RemoteAddr()).WithError(err).Warn("protocol.ReadPreface() failed")
+		return
+	}
+
+	encoder, err := protocol.NewEncoder(encoding, connection)
+	if err != nil {
+		log.WithError(err).Error("protocol.NewEncoder() failed")
+		return
+	}
+
+	decoder, err := protocol.NewDecoder(encoding, reader)
+	if err != nil {
+		log.WithError(err).Error("protocol.NewDecoder() failed")
+		return
+	}
+
+	username, hello, err := handshake(config.Authenticator, serverCapabilities(locks), encoder, decoder)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"address":  connection.RemoteAddr(),
+			"username": username,
+			"version":  hello.Version,
+		}).WithError(err).Warn("Handshake failed")
+		return
+	}
+	connection.SetDeadline(time.Time{})
+
+	log.WithFields(log.Fields{
+		"address":      connection.RemoteAddr(),
+		"username":     username,
+		"encoding":     encoding,
+		"version":      hello.Version,
+		"capabilities": hello.Capabilities,
+	}).Info("Authenticated")
+
+	messageHandler := &FileboxMessageHandler{
+		BasePath:     config.BasePath,
+		Username:     username,
+		Capabilities: hello.Capabilities,
+		Policy:       config.Policy,
+		ReadOnly:     config.ReadOnly,
+		Quota:        config.Quota,
+		Notifier:     notifier,
+		Locks:        locks,
+		Quotas:       quotas,
+
+		AllXattrNamespaces: config.AllXattrNamespaces,
+		AllowSetid:         config.AllowSetid,
+	}
+
+	client := newClientConnection(connection, encoder)
+	go client.writeMessages()
+	defer client.close()
+
+	// Clients that don't accept notifications still trigger them, but
+	// never receive them.
+	if hello.Capabilities.Has(protocol.CapabilityNotifications) {
+		notifier.subscribe(messageHandler, client)
+		defer notifier.unsubscribe(messageHandler)
+	}
+
+	maxInFlight := config.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = DefaultMaxInFlight
+	}
+	inFlight := make(chan struct{}, maxInFlight)
+
+	// Wait for the requests that are still being handled before closing
+	// the connection's files, so none of them opens a file after that.
+	var pendingMessages sync.WaitGroup
+	defer messageHandler.Close()
+	defer pendingMessages.Wait()
+
 	for {
 		message := &protocol.Message{}
 
@@ -94,22 +312,70 @@ func handleConnection(messageHandler *FileboxMessageHandler, connection net.Conn
 			return
 		}
 
-		go handleMessage(messageHandler, encoder, message)
+		// Block until there's room for another request. Meanwhile, TCP flow
+		// control slows down the client.
+		inFlight <- struct{}{}
+
+		pendingMessages.Add(1)
+		go func() {
+			defer pendingMessages.Done()
+			defer func() { <-inFlight }()
+
+			handleMessage(messageHandler, client, message)
+		}()
 	}
 }
 
-func RunServer(basePath string, port uint16) {
-	listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", port))
+func RunServer(config Config) {
+	listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", config.Port))
 	if err != nil {
-		log.WithError(err).WithField("port", port).Error("net.Listen() failed")
+		log.WithError(err).WithField("port", config.Port).Error("net.Listen() failed")
 		return
 	}
 
+	Serve(listener, config)
+}
+
+// Serve handles the connections of a listener until it's closed. The
+// connections that were already accepted keep being served until their
+// clients disconnect.
+func Serve(listener net.Listener, config Config) {
+	if config.TLSConfig != nil {
+		listener = tls.NewListener(listener, config.TLSConfig)
+	}
+
 	defer listener.Close()
 
-	messageHandler := &FileboxMessageHandler{BasePath: basePath}
+	notifier := NewNotifier()
+	locks := NewLockManager(config.MandatoryLocks)
+
+	// The storage that the shared directory uses is also counted for Quota,
+	// which reports the free space under the cap.
+	var quotas *QuotaManager
+	if config.Quotas != nil || config.Quota > 0 {
+		quotaConfig := config.Quotas
+		if quotaConfig == nil {
+			quotaConfig = &QuotaConfig{}
+		}
+
+		var err error
+		quotas, err = NewQuotaManager(config.BasePath, quotaConfig)
+		if err != nil {
+			log.WithError(err).WithField("path", config.BasePath).Error("NewQuotaManager() failed")
+			return
+		}
+	}
+
+	if config.GRPCPort != 0 {
+		go runGRPCServer(&config, notifier, locks, quotas)
+	}
 
-	log.WithField("port", port).Info("Started.")
+	log.WithFields(log.Fields{
+		"address":         listener.Addr(),
+		"tls":             config.TLSConfig != nil,
+		"read_only":       config.ReadOnly,
+		"mandatory_locks": config.MandatoryLocks,
+	}).Info("Started.")
 
 	for {
 		connection, err := listener.Accept()
@@ -118,6 +384,6 @@ func RunServer(basePath string, port uint16) {
 			return
 		}
 
-		go handleConnection(messageHandler, connection)
+		go handleConnection(&config, notifier, locks, quotas, connection)
 	}
 }
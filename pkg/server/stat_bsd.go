@@ -0,0 +1,26 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+package server
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// statAttributes fills the attributes of a file that os.FileInfo doesn't have.
+func statAttributes(file os.FileInfo, fileInfo *protocol.FileInfo) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+
+	fileInfo.Uid = stat.Uid
+	fileInfo.Gid = stat.Gid
+	fileInfo.Nlink = uint32(stat.Nlink)
+	fileInfo.AccessTime = time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec))
+	fileInfo.ChangeTime = time.Unix(int64(stat.Ctimespec.Sec), int64(stat.Ctimespec.Nsec))
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !openbsd && !dragonfly && !solaris && !darwin && !freebsd && !netbsd && !windows
+// +build !linux,!openbsd,!dragonfly,!solaris,!darwin,!freebsd,!netbsd,!windows
+
+package server
+
+import (
+	"os"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// statAttributes does nothing on platforms whose stat structure isn't known.
+func statAttributes(file os.FileInfo, fileInfo *protocol.FileInfo) {}
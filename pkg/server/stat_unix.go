@@ -0,0 +1,26 @@
+//go:build linux || openbsd || dragonfly || solaris
+// +build linux openbsd dragonfly solaris
+
+package server
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// statAttributes fills the attributes of a file that os.FileInfo doesn't have.
+func statAttributes(file os.FileInfo, fileInfo *protocol.FileInfo) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+
+	fileInfo.Uid = stat.Uid
+	fileInfo.Gid = stat.Gid
+	fileInfo.Nlink = uint32(stat.Nlink)
+	fileInfo.AccessTime = time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+	fileInfo.ChangeTime = time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// statAttributes fills the attributes of a file that os.FileInfo doesn't have.
+// Windows has no owner IDs or status change time, so the creation time is
+// used as the change time. The number of hard links isn't known without
+// opening the file, so it's always 1.
+func statAttributes(file os.FileInfo, fileInfo *protocol.FileInfo) {
+	data, ok := file.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return
+	}
+
+	fileInfo.Nlink = 1
+	fileInfo.AccessTime = time.Unix(0, data.LastAccessTime.Nanoseconds())
+	fileInfo.ChangeTime = time.Unix(0, data.CreationTime.Nanoseconds())
+}
@@ -0,0 +1,34 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package server
+
+import (
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"golang.org/x/sys/unix"
+)
+
+// diskUsage returns the size of the file system that contains path.
+func diskUsage(path string) (*protocol.StatfsResponse, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+
+	// The fields have different types on every BSD. FreeBSD reports negative
+	// free space when the reserved blocks are in use.
+	available := int64(stat.Bavail)
+	if available < 0 {
+		available = 0
+	}
+
+	return &protocol.StatfsResponse{
+		BlockSize:       uint64(stat.Bsize),
+		Blocks:          uint64(stat.Blocks),
+		BlocksFree:      uint64(stat.Bfree),
+		BlocksAvailable: uint64(available),
+		Files:           uint64(stat.Files),
+		FilesFree:       uint64(stat.Ffree),
+		NameLength:      255,
+	}, nil
+}
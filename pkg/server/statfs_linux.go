@@ -0,0 +1,32 @@
+package server
+
+import (
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// diskUsage returns the size of the file system that contains path.
+func diskUsage(path string) (*protocol.StatfsResponse, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, err
+	}
+
+	// Block counts are in units of the fragment size, which older kernels
+	// don't report.
+	blockSize := uint64(stat.Frsize)
+	if blockSize == 0 {
+		blockSize = uint64(stat.Bsize)
+	}
+
+	return &protocol.StatfsResponse{
+		BlockSize:       blockSize,
+		Blocks:          stat.Blocks,
+		BlocksFree:      stat.Bfree,
+		BlocksAvailable: stat.Bavail,
+		Files:           stat.Files,
+		FilesFree:       stat.Ffree,
+		NameLength:      uint64(stat.Namelen),
+	}, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !windows
+// +build !linux,!darwin,!freebsd,!windows
+
+package server
+
+import (
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+// diskUsage isn't supported on platforms whose statfs structure isn't known.
+func diskUsage(path string) (*protocol.StatfsResponse, error) {
+	return nil, syscall.ENOTSUP
+}
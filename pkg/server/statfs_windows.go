@@ -0,0 +1,41 @@
+package server
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// statfsBlockSize is the block size that is reported on Windows, which only
+// reports the size of the disk in bytes.
+const statfsBlockSize = 4096
+
+// diskUsage returns the size of the disk that contains path. NTFS doesn't
+// limit the number of files, so Files is 0.
+func diskUsage(path string) (*protocol.StatfsResponse, error) {
+	pathp, err := syscall.UTF16PtrFromString(fixLongPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var available, total, free uint64
+	result, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(pathp)),
+		uintptr(unsafe.Pointer(&available)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)))
+	if result == 0 {
+		return nil, err
+	}
+
+	return &protocol.StatfsResponse{
+		BlockSize:       statfsBlockSize,
+		Blocks:          total / statfsBlockSize,
+		BlocksFree:      free / statfsBlockSize,
+		BlocksAvailable: available / statfsBlockSize,
+		NameLength:      255,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+// NewTLSConfig creates the TLS configuration of the server from PEM files.
+// If clientCAFile isn't empty, client certificates are verified against it,
+// and if requireClientCert is set, clients without a valid certificate are
+// rejected.
+func NewTLSConfig(certFile string, keyFile string, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile == "" {
+		if requireClientCert {
+			return nil, errors.New("a client CA file is required in order to verify client certificates")
+		}
+
+		return config, nil
+	}
+
+	pem, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in the client CA file")
+	}
+
+	if requireClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return config, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bytes"
+	"syscall"
+
+	"github.com/alongubkin/filebox/pkg/protocol"
+	"golang.org/x/sys/unix"
+)
+
+// getXattr reads an extended attribute. The size of the value isn't known in
+// advance, so it's read again if the attribute grows in between.
+func getXattr(path string, name string) ([]byte, error) {
+	for {
+		size, err := unix.Getxattr(path, name, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		value := make([]byte, size)
+		size, err = unix.Getxattr(path, name, value)
+		if err == syscall.ERANGE {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return value[:size], nil
+	}
+}
+
+// listXattrs returns the names of the extended attributes of a file.
+func listXattrs(path string) ([]string, error) {
+	for {
+		size, err := unix.Listxattr(path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		list := make([]byte, size)
+		size, err = unix.Listxattr(path, list)
+		if err == syscall.ERANGE {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// The names are separated by NUL bytes.
+		var names []string
+		for _, name := range bytes.Split(list[:size], []byte{0}) {
+			if len(name) > 0 {
+				names = append(names, string(name))
+			}
+		}
+
+		return names, nil
+	}
+}
+
+func setXattr(path string, name string, value []byte, flags int) error {
+	var xattrFlags int
+	if flags&protocol.XattrCreate != 0 {
+		xattrFlags |= unix.XATTR_CREATE
+	}
+	if flags&protocol.XattrReplace != 0 {
+		xattrFlags |= unix.XATTR_REPLACE
+	}
+
+	return unix.Setxattr(path, name, value, xattrFlags)
+}
+
+func removeXattr(path string, name string) error {
+	return unix.Removexattr(path, name)
+}
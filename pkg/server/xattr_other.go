@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package server
+
+import "syscall"
+
+// Extended attributes are only supported on Linux servers.
+
+func getXattr(path string, name string) ([]byte, error) {
+	return nil, syscall.ENOTSUP
+}
+
+func listXattrs(path string) ([]string, error) {
+	return nil, syscall.ENOTSUP
+}
+
+func setXattr(path string, name string, value []byte, flags int) error {
+	return syscall.ENOTSUP
+}
+
+func removeXattr(path string, name string) error {
+	return syscall.ENOTSUP
+}
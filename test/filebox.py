@@ -8,11 +8,19 @@ import time
 import platform
 import glob
 import uuid
+from filebox_protobuf import ProtobufClient
 
 FILEBOX_BASE_PATH = os.path.join(os.path.dirname(os.path.abspath(__file__)), '..')
 FILEBOX_TEST_PORT = 8763
 CLIENTS = 5
 
+# Error codes from pkg/protocol/errors.go
+ERROR_NOT_FOUND = 2
+ERROR_PERMISSION = 3
+ERROR_NO_ATTRIBUTE = 18
+ERROR_LOCKED = 19
+ERROR_QUOTA_EXCEEDED = 20
+
 def get_filebox_executable(exe_type):
   ARCH = {
     'i386': '386',
@@ -38,29 +46,30 @@ def check_socket(host, port):
 
 
 @contextlib.contextmanager
-def filebox_server():
-  assert(not check_socket('localhost', FILEBOX_TEST_PORT))
+def filebox_server(port=FILEBOX_TEST_PORT, arguments=()):
+  assert(not check_socket('localhost', port))
 
   with tempfile.TemporaryDirectory() as server_directory:
     server_process = subprocess.Popen([
       get_filebox_executable('filebox-server'),
       '--path', server_directory,
-      '--port', str(FILEBOX_TEST_PORT),
+      '--port', str(port),
       '--verbose',
-    ])
+    ] + list(arguments))
 
     try:
       # Wait until the port is open
-      while not check_socket('localhost', FILEBOX_TEST_PORT):
+      while not check_socket('localhost', port):
         time.sleep(0.1)
 
       yield server_directory
     finally:
       server_process.kill()
+      server_process.wait()
 
 
 @contextlib.contextmanager
-def filebox_client():
+def filebox_client(encoding='gob'):
   assert(check_socket('localhost', FILEBOX_TEST_PORT))
 
   client_directory = os.path.join(tempfile.gettempdir(), str(uuid.uuid4()))
@@ -71,6 +80,7 @@ def filebox_client():
     get_filebox_executable('filebox-client'),
     '--mountpoint', client_directory,
     '--address', 'localhost:{}'.format(FILEBOX_TEST_PORT),
+    '--encoding', encoding,
     '--verbose',
    ])
 
@@ -86,11 +96,43 @@ def filebox_client():
     except:
       pass
 
-@pytest.fixture(scope="module")
-def shared_directories():
+# Every test that uses the shared directories runs once for each encoding of
+# the protocol.
+@pytest.fixture(scope="module", params=['gob', 'protobuf'])
+def shared_directories(request):
   with contextlib.ExitStack() as stack:
     managers = [stack.enter_context(filebox_server())]
     for _ in range(CLIENTS):
-      managers.append(stack.enter_context(filebox_client()))
+      managers.append(stack.enter_context(filebox_client(request.param)))
 
     yield managers
+
+
+# The tests that send requests through the protobuf encoding share a server
+# in each module, and connect to it with connect() or the client fixture.
+@pytest.fixture(scope="module")
+def server_directory():
+  with filebox_server() as directory:
+    yield directory
+
+
+def connect(port=FILEBOX_TEST_PORT, username='python', token=''):
+  client = ProtobufClient(('localhost', port))
+  try:
+    name, hello = client.request('HelloRequest', version=1, min_version=1, capabilities=['notifications'])
+    assert name == 'HelloResponse'
+    assert hello['version'] == 1
+
+    client.request('AuthenticateRequest', username=username, token=token)
+  except:
+    client.close()
+    raise
+
+  return client
+
+
+@pytest.fixture
+def client(server_directory):
+  client = connect()
+  yield client
+  client.close()
@@ -0,0 +1,249 @@
+"""
+A minimal Filebox client that speaks the protobuf encoding of the protocol
+(pkg/protocol/pb/filebox.proto). It implements just enough of the protobuf
+wire format for the Filebox messages, so it doesn't need any dependencies.
+"""
+
+import socket
+import struct
+
+PREFACE = b'\x00FBP'
+
+# Field types
+VARINT, SINT, STRING, BYTES, REPEATED_STRING = 'varint', 'sint', 'string', 'bytes', 'repeated_string'
+
+FILE_INFO = {
+  'name': (1, STRING),
+  'size': (2, SINT),
+  'mode': (3, VARINT),
+  'mod_time': (4, SINT),
+  'is_dir': (5, VARINT),
+  'uid': (6, VARINT),
+  'gid': (7, VARINT),
+  'access_time': (8, SINT),
+  'change_time': (9, SINT),
+  'nlink': (10, VARINT),
+}
+
+# name: (message type, fields). Nested messages are (number, fields) or
+# (number, [fields]) for repeated ones.
+MESSAGES = {
+  'EmptyResponse': (0, {}),
+  'HelloRequest': (1, {'version': (1, VARINT), 'min_version': (2, VARINT), 'capabilities': (3, REPEATED_STRING)}),
+  'HelloResponse': (2, {'version': (1, VARINT), 'capabilities': (2, REPEATED_STRING)}),
+  'AuthenticateRequest': (3, {'username': (1, STRING), 'token': (2, STRING)}),
+  'AuthenticateResponse': (4, {'username': (1, STRING)}),
+  'OpenFileRequest': (5, {'path': (1, STRING), 'flags': (2, SINT)}),
+  'OpenFileResponse': (6, {'file_handle': (1, VARINT)}),
+  'ReadFileRequest': (7, {'file_handle': (1, VARINT), 'offset': (2, SINT), 'size': (3, SINT)}),
+  'ReadFileResponse': (8, {'data': (1, BYTES), 'bytes_read': (2, SINT)}),
+  'ReadDirectoryRequest': (9, {'path': (1, STRING), 'after': (2, STRING), 'limit': (3, SINT)}),
+  'ReadDirectoryResponse': (10, {'files': (1, [FILE_INFO]), 'more': (2, VARINT)}),
+  'GetFileAttributesRequest': (11, {'path': (1, STRING), 'file_handle': (2, VARINT)}),
+  'GetFileAttributesResponse': (12, {'file_info': (1, FILE_INFO)}),
+  'CloseFileRequest': (13, {'file_handle': (1, VARINT)}),
+  'CreateDirectoryRequest': (14, {'path': (1, STRING), 'mode': (2, VARINT)}),
+  'CreateFileRequest': (15, {'path': (1, STRING)}),
+  'RenameRequest': (16, {'old_path': (1, STRING), 'new_path': (2, STRING)}),
+  'DeleteDirectoryRequest': (17, {'path': (1, STRING)}),
+  'TruncateRequest': (18, {'path': (1, STRING), 'file_handle': (2, VARINT), 'size': (3, SINT)}),
+  'DeleteFileRequest': (19, {'path': (1, STRING)}),
+  'WriteFileRequest': (20, {'file_handle': (1, VARINT), 'offset': (2, SINT), 'data': (3, BYTES)}),
+  'WriteFileResponse': (21, {'bytes_written': (1, SINT)}),
+  'ChangeNotification': (22, {'type': (1, SINT), 'path': (2, STRING), 'new_path': (3, STRING)}),
+  'FsyncFileRequest': (23, {'file_handle': (1, VARINT)}),
+  'FsyncDirectoryRequest': (24, {'path': (1, STRING)}),
+  'ChangeModeRequest': (25, {'path': (1, STRING), 'mode': (2, VARINT)}),
+  'ChangeOwnerRequest': (26, {'path': (1, STRING), 'uid': (2, SINT), 'gid': (3, SINT)}),
+  'CreateSymlinkRequest': (28, {'path': (1, STRING), 'target': (2, STRING)}),
+  'ReadSymlinkRequest': (29, {'path': (1, STRING)}),
+  'ReadSymlinkResponse': (30, {'target': (1, STRING)}),
+  'CreateHardLinkRequest': (31, {'old_path': (1, STRING), 'new_path': (2, STRING)}),
+  'GetExtendedAttributeRequest': (32, {'path': (1, STRING), 'name': (2, STRING)}),
+  'GetExtendedAttributeResponse': (33, {'value': (1, BYTES)}),
+  'ListExtendedAttributesRequest': (34, {'path': (1, STRING)}),
+  'ListExtendedAttributesResponse': (35, {'names': (1, REPEATED_STRING)}),
+  'SetExtendedAttributeRequest': (36, {'path': (1, STRING), 'name': (2, STRING), 'value': (3, BYTES), 'flags': (4, SINT)}),
+  'RemoveExtendedAttributeRequest': (37, {'path': (1, STRING), 'name': (2, STRING)}),
+  'LockRequest': (38, {'file_handle': (1, VARINT), 'kind': (2, SINT), 'type': (3, SINT), 'start': (4, SINT), 'length': (5, SINT), 'owner': (6, VARINT)}),
+  'UnlockRequest': (39, {'file_handle': (1, VARINT), 'kind': (2, SINT), 'start': (3, SINT), 'length': (4, SINT), 'owner': (5, VARINT)}),
+  'TestLockRequest': (40, {'file_handle': (1, VARINT), 'kind': (2, SINT), 'type': (3, SINT), 'start': (4, SINT), 'length': (5, SINT), 'owner': (6, VARINT)}),
+  'TestLockResponse': (41, {'type': (1, SINT), 'start': (2, SINT), 'length': (3, SINT)}),
+  'StatfsRequest': (42, {'path': (1, STRING)}),
+  'StatfsResponse': (43, {'block_size': (1, VARINT), 'blocks': (2, VARINT), 'blocks_free': (3, VARINT), 'blocks_available': (4, VARINT), 'files': (5, VARINT), 'files_free': (6, VARINT), 'name_length': (7, VARINT)}),
+  'QuotaUsageRequest': (44, {}),
+  'QuotaUsageResponse': (45, {'quotas': (1, [{'user': (1, STRING), 'path': (2, STRING), 'limit': (3, SINT), 'usage': (4, SINT)}])}),
+  'ChangeTimesRequest': (27, {'path': (1, STRING), 'access_time': (2, SINT), 'mod_time': (3, SINT)}),
+}
+
+MESSAGE_NAMES = {message_type: name for name, (message_type, _) in MESSAGES.items()}
+
+ENVELOPE = {
+  'message_id': (1, VARINT),
+  'is_response': (2, VARINT),
+  'type': (3, VARINT),
+  'data': (4, BYTES),
+  'error_code': (5, VARINT),
+  'error_message': (6, STRING),
+}
+
+# Sent instead of a file handle in requests that use a path.
+NO_FILE_HANDLE = 2**64 - 1
+
+
+def encode_varint(value):
+  value &= 2**64 - 1
+  result = bytearray()
+  while True:
+    byte = value & 0x7f
+    value >>= 7
+    if value:
+      result.append(byte | 0x80)
+    else:
+      result.append(byte)
+      return bytes(result)
+
+
+def decode_varint(data, offset):
+  value, shift = 0, 0
+  while True:
+    byte = data[offset]
+    offset += 1
+    value |= (byte & 0x7f) << shift
+    shift += 7
+    if not byte & 0x80:
+      return value, offset
+
+
+def encode_message(fields, values):
+  result = bytearray()
+  for name, value in values.items():
+    number, kind = fields[name]
+    if isinstance(kind, list):
+      for item in value:
+        encoded = encode_message(kind[0], item)
+        result += encode_varint(number << 3 | 2) + encode_varint(len(encoded)) + encoded
+    elif isinstance(kind, dict):
+      encoded = encode_message(kind, value)
+      result += encode_varint(number << 3 | 2) + encode_varint(len(encoded)) + encoded
+    elif kind == REPEATED_STRING:
+      for item in value:
+        encoded = item.encode()
+        result += encode_varint(number << 3 | 2) + encode_varint(len(encoded)) + encoded
+    elif kind in (STRING, BYTES):
+      encoded = value.encode() if kind == STRING else value
+      result += encode_varint(number << 3 | 2) + encode_varint(len(encoded)) + encoded
+    else:
+      result += encode_varint(number << 3) + encode_varint(int(value))
+  return bytes(result)
+
+
+def decode_message(fields, data):
+  by_number = {number: (name, kind) for name, (number, kind) in fields.items()}
+  values = {}
+  for name, (_, kind) in fields.items():
+    if isinstance(kind, list) or kind == REPEATED_STRING:
+      values[name] = []
+    elif isinstance(kind, dict):
+      values[name] = None
+    elif kind == STRING:
+      values[name] = ''
+    elif kind == BYTES:
+      values[name] = b''
+    else:
+      values[name] = 0
+
+  offset = 0
+  while offset < len(data):
+    key, offset = decode_varint(data, offset)
+    number, wire_type = key >> 3, key & 7
+
+    if wire_type == 0:
+      value, offset = decode_varint(data, offset)
+    elif wire_type == 2:
+      size, offset = decode_varint(data, offset)
+      value, offset = data[offset:offset + size], offset + size
+    else:
+      raise ValueError('unsupported wire type {}'.format(wire_type))
+
+    if number not in by_number:
+      continue
+
+    name, kind = by_number[number]
+    if isinstance(kind, list):
+      values[name].append(decode_message(kind[0], value))
+    elif isinstance(kind, dict):
+      values[name] = decode_message(kind, value)
+    elif kind == REPEATED_STRING:
+      values[name].append(value.decode())
+    elif kind == STRING:
+      values[name] = value.decode()
+    elif kind == BYTES:
+      values[name] = value
+    elif kind == SINT:
+      values[name] = value - 2**64 if value >= 2**63 else value
+    else:
+      values[name] = value
+
+  return values
+
+
+class FileboxError(Exception):
+  def __init__(self, code, message):
+    super().__init__(message)
+    self.code = code
+
+
+class ProtobufClient:
+  """
+  Sends requests one by one, and returns (message name, fields) for every
+  response. Change notifications that arrive meanwhile are collected in
+  notifications.
+  """
+
+  def __init__(self, address):
+    self.socket = socket.create_connection(address)
+    self.socket.sendall(PREFACE)
+    self.next_message_id = 1
+    self.notifications = []
+
+  def close(self):
+    self.socket.close()
+
+  def send(self, message_name, **values):
+    message_type, fields = MESSAGES[message_name]
+    envelope = encode_message(ENVELOPE, {
+      'message_id': self.next_message_id,
+      'type': message_type,
+      'data': encode_message(fields, values),
+    })
+    self.next_message_id += 1
+    self.socket.sendall(struct.pack('>I', len(envelope)) + envelope)
+
+  def receive(self):
+    size, = struct.unpack('>I', self.recv_exactly(4))
+    envelope = decode_message(ENVELOPE, self.recv_exactly(size))
+    name = MESSAGE_NAMES[envelope['type']]
+    return envelope, name, decode_message(MESSAGES[name][1], envelope['data'])
+
+  def request(self, message_name, **values):
+    self.send(message_name, **values)
+    while True:
+      envelope, response_name, response = self.receive()
+      if envelope['is_response']:
+        break
+      self.notifications.append(response)
+
+    if envelope['error_code']:
+      raise FileboxError(envelope['error_code'], envelope['error_message'])
+
+    return response_name, response
+
+  def recv_exactly(self, size):
+    data = bytearray()
+    while len(data) < size:
+      chunk = self.socket.recv(size - len(data))
+      if not chunk:
+        raise ConnectionError('connection closed')
+      data += chunk
+    return bytes(data)
@@ -0,0 +1,85 @@
+import os
+import time
+import pytest
+from filebox import server_directory, connect, client, FILEBOX_TEST_PORT, ERROR_LOCKED
+from filebox_protobuf import ProtobufClient, FileboxError
+
+# Lock kinds and types from pkg/protocol/messages.go
+LOCK_RANGE = 1
+LOCK_FILE = 2
+LOCK_SHARED = 1
+LOCK_EXCLUSIVE = 2
+
+
+def test_locks_capability(server_directory):
+  client = ProtobufClient(('localhost', FILEBOX_TEST_PORT))
+  try:
+    _, hello = client.request('HelloRequest', version=1, min_version=1, capabilities=['notifications', 'locks'])
+    assert 'locks' in hello['capabilities']
+  finally:
+    client.close()
+
+
+def test_locks(server_directory, client):
+  open(os.path.join(server_directory, 'locked.txt'), 'w').close()
+  holder = connect()
+
+  try:
+    _, response = holder.request('OpenFileRequest', path='/locked.txt', flags=os.O_RDWR)
+    holder.request('LockRequest', file_handle=response['file_handle'], kind=LOCK_RANGE, type=LOCK_EXCLUSIVE, start=0, length=10, owner=1)
+  except:
+    holder.close()
+    raise
+
+  try:
+    _, response = client.request('OpenFileRequest', path='/locked.txt', flags=os.O_RDWR)
+    file_handle = response['file_handle']
+
+    with pytest.raises(FileboxError) as error:
+      client.request('LockRequest', file_handle=file_handle, kind=LOCK_RANGE, type=LOCK_SHARED, start=5, length=10, owner=1)
+
+    assert error.value.code == ERROR_LOCKED
+
+    _, response = client.request('TestLockRequest', file_handle=file_handle, kind=LOCK_RANGE, type=LOCK_SHARED, start=0, length=0, owner=1)
+    assert response == {'type': LOCK_EXCLUSIVE, 'start': 0, 'length': 10}
+
+    # Byte range locks and whole file locks don't conflict.
+    client.request('LockRequest', file_handle=file_handle, kind=LOCK_FILE, type=LOCK_EXCLUSIVE, start=0, length=0, owner=0)
+  finally:
+    holder.close()
+
+  # Disconnecting releases the locks of the connection, once the server
+  # notices it.
+  for _ in range(50):
+    try:
+      client.request('LockRequest', file_handle=file_handle, kind=LOCK_RANGE, type=LOCK_SHARED, start=5, length=10, owner=1)
+      break
+    except FileboxError as error:
+      assert error.code == ERROR_LOCKED
+      time.sleep(0.1)
+  else:
+    pytest.fail('the locks of a closed connection were not released')
+
+
+def test_locks_released_on_close(server_directory, client):
+  open(os.path.join(server_directory, 'close-locked.txt'), 'w').close()
+  other = connect()
+
+  try:
+    handles = []
+    for _ in range(2):
+      _, response = client.request('OpenFileRequest', path='/close-locked.txt', flags=os.O_RDWR)
+      handles.append(response['file_handle'])
+
+    client.request('LockRequest', file_handle=handles[0], kind=LOCK_RANGE, type=LOCK_EXCLUSIVE, start=0, length=10, owner=7)
+    client.request('LockRequest', file_handle=handles[1], kind=LOCK_RANGE, type=LOCK_EXCLUSIVE, start=20, length=10, owner=7)
+
+    # Like close(2), closing one handle releases the owner's locks through
+    # both of them.
+    client.request('CloseFileRequest', file_handle=handles[1])
+
+    _, response = other.request('OpenFileRequest', path='/close-locked.txt', flags=os.O_RDWR)
+    other.request('LockRequest', file_handle=response['file_handle'], kind=LOCK_RANGE, type=LOCK_EXCLUSIVE, start=0, length=0, owner=1)
+    client.request('CloseFileRequest', file_handle=handles[0])
+  finally:
+    other.close()
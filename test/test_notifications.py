@@ -0,0 +1,102 @@
+import os
+import json
+import tempfile
+import pytest
+from filebox import filebox_server, server_directory, connect, client, FILEBOX_TEST_PORT, ERROR_PERMISSION
+from filebox_protobuf import FileboxError
+
+# Change types from pkg/protocol/messages.go
+CHANGE_WRITTEN = 2
+
+
+def test_write_notifications_are_coalesced(server_directory, client):
+  open(os.path.join(server_directory, 'coalesced.txt'), 'w').close()
+  listener = connect()
+
+  try:
+    _, response = client.request('OpenFileRequest', path='/coalesced.txt', flags=os.O_RDWR)
+    file_handle = response['file_handle']
+    for i in range(3):
+      client.request('WriteFileRequest', file_handle=file_handle, offset=i * 5, data=b'chunk')
+    client.request('CloseFileRequest', file_handle=file_handle)
+
+    # Notifications are sent before the response to the request that caused
+    # them, so they arrive before the response to the next request.
+    listener.request('StatfsRequest', path='/')
+    written = [n for n in listener.notifications if n['path'] == '/coalesced.txt']
+    assert written == [{'type': CHANGE_WRITTEN, 'path': '/coalesced.txt', 'new_path': ''}]
+  finally:
+    listener.close()
+
+
+@pytest.fixture(scope="module")
+def policy_server():
+  """
+  A server where bob can't read /private, and nobody can delete or rename
+  /archive/keep.
+  """
+
+  with tempfile.TemporaryDirectory() as config_directory:
+    tokens_file = os.path.join(config_directory, 'tokens.txt')
+    with open(tokens_file, 'w') as f:
+      f.write('alice:alice-token\nbob:bob-token\n')
+
+    policy_file = os.path.join(config_directory, 'policy.json')
+    with open(policy_file, 'w') as f:
+      json.dump({'rules': [
+        {'user': 'bob', 'path': '/private', 'deny': ['read']},
+        {'user': '*', 'path': '/archive/keep', 'deny': ['delete', 'rename']},
+      ]}, f)
+
+    port = FILEBOX_TEST_PORT + 2
+    with filebox_server(port, ['--tokens-file', tokens_file, '--policy', policy_file]) as directory:
+      yield directory, port
+
+
+def test_notifications_follow_policy(policy_server):
+  _, port = policy_server
+  alice = connect(port, 'alice', 'alice-token')
+  bob = connect(port, 'bob', 'bob-token')
+
+  try:
+    alice.request('CreateDirectoryRequest', path='/private', mode=0o755)
+    alice.request('CreateFileRequest', path='/private/secret.txt')
+    alice.request('CreateFileRequest', path='/public.txt')
+
+    bob.request('StatfsRequest', path='/')
+    assert sorted(n['path'] for n in bob.notifications) == ['/public.txt']
+  finally:
+    alice.close()
+    bob.close()
+
+
+def test_policy_protects_descendants(policy_server):
+  """
+  Deleting or renaming a directory can't get around a rule under it, and a
+  rename can't replace a file that can't be deleted.
+  """
+  server_directory, port = policy_server
+  alice = connect(port, 'alice', 'alice-token')
+
+  try:
+    alice.request('CreateDirectoryRequest', path='/archive', mode=0o755)
+    alice.request('CreateDirectoryRequest', path='/archive/keep', mode=0o755)
+    alice.request('CreateFileRequest', path='/archive/keep/important')
+    alice.request('CreateFileRequest', path='/other.txt')
+
+    for request, fields in [
+      ('DeleteFileRequest', {'path': '/archive/keep/important'}),
+      ('DeleteDirectoryRequest', {'path': '/archive'}),
+      ('RenameRequest', {'old_path': '/archive', 'new_path': '/moved'}),
+      ('RenameRequest', {'old_path': '/other.txt', 'new_path': '/archive/keep/important'}),
+    ]:
+      with pytest.raises(FileboxError) as error:
+        alice.request(request, **fields)
+
+      assert error.value.code == ERROR_PERMISSION
+      assert os.path.exists(os.path.join(server_directory, 'archive', 'keep', 'important'))
+
+    # Renaming to a new name next to the protected directory is allowed.
+    alice.request('RenameRequest', old_path='/other.txt', new_path='/archive/other.txt')
+  finally:
+    alice.close()
@@ -0,0 +1,156 @@
+import os
+import shutil
+import pytest
+from filebox import server_directory, client, ERROR_NOT_FOUND, ERROR_PERMISSION
+from filebox_protobuf import FileboxError, MESSAGES, NO_FILE_HANDLE
+
+
+# Values for the fields of requests that aren't paths, so every request that
+# takes a path can be sent with a hostile one.
+PATH_REQUEST_DEFAULTS = {
+  'OpenFileRequest': {'flags': os.O_CREAT | os.O_RDWR},
+  'GetFileAttributesRequest': {'file_handle': NO_FILE_HANDLE},
+  'CreateDirectoryRequest': {'mode': 0o755},
+  'TruncateRequest': {'file_handle': NO_FILE_HANDLE, 'size': 0},
+  'ChangeModeRequest': {'mode': 0o777},
+  'ChangeOwnerRequest': {'uid': -1, 'gid': -1},
+  'ChangeTimesRequest': {'access_time': 0, 'mod_time': 0},
+  'CreateSymlinkRequest': {'target': 'inside.txt'},
+  'GetExtendedAttributeRequest': {'name': 'user.color'},
+  'SetExtendedAttributeRequest': {'name': 'user.color', 'value': b'blue', 'flags': 0},
+  'RemoveExtendedAttributeRequest': {'name': 'user.color'},
+}
+
+
+PATH_FIELDS = [
+  (name, field)
+  for name, (_, fields) in sorted(MESSAGES.items())
+  if name.endswith('Request')
+  for field in ('path', 'old_path', 'new_path')
+  if field in fields
+]
+
+
+# Paths that lead outside of the shared directory. "{outside}" is the name of
+# a directory next to it, and "/escape" is a symbolic link in the shared
+# directory that points to that directory.
+HOSTILE_PATHS = {
+  'dot-dot': '../{outside}/victim',
+  'rooted-dot-dot': '/../{outside}/victim',
+  'nested-dot-dot': '/inside/../../{outside}/victim',
+  'symlink': '/escape/victim',
+  'symlink-parent': '/escape/new/victim',
+  'nul-byte': '/inside.txt\x00/../../{outside}/victim',
+  'nul-suffix': '/inside.txt\x00',
+}
+
+
+@pytest.fixture
+def outside_directory(server_directory):
+  outside = server_directory + '-outside'
+  os.mkdir(outside)
+  with open(os.path.join(outside, 'victim'), 'w') as f:
+    f.write('untouched')
+
+  os.mkdir(os.path.join(server_directory, 'inside'))
+  with open(os.path.join(server_directory, 'inside.txt'), 'w') as f:
+    f.write('inside')
+  os.symlink(outside, os.path.join(server_directory, 'escape'))
+
+  try:
+    yield outside
+  finally:
+    shutil.rmtree(outside)
+    shutil.rmtree(os.path.join(server_directory, 'inside'))
+    for name in ['inside.txt', 'escape']:
+      os.remove(os.path.join(server_directory, name))
+
+
+def snapshot(directory):
+  """Returns the names, contents and metadata of everything in a directory."""
+  result = {}
+  for parent, directories, files in os.walk(directory):
+    for name in directories + files:
+      path = os.path.join(parent, name)
+      info = os.lstat(path)
+      content = None
+      if os.path.isfile(path):
+        with open(path, 'rb') as f:
+          content = f.read()
+      result[os.path.relpath(path, directory)] = (info.st_mode, info.st_uid, info.st_gid, info.st_mtime_ns, content)
+  return result
+
+
+@pytest.mark.parametrize('hostile', sorted(HOSTILE_PATHS))
+@pytest.mark.parametrize('request_name,field', PATH_FIELDS)
+def test_hostile_paths(server_directory, outside_directory, client, request_name, field, hostile):
+  """
+  Every request that takes a path must reject paths that lead outside of the
+  shared directory with ErrorPermission, and must not touch anything there.
+  """
+
+  values = {'path': '/inside.txt', 'old_path': '/inside.txt', 'new_path': '/inside-new.txt'}
+  values = {name: values[name] for name in ('path', 'old_path', 'new_path') if name in MESSAGES[request_name][1]}
+  values.update(PATH_REQUEST_DEFAULTS.get(request_name, {}))
+  values[field] = HOSTILE_PATHS[hostile].format(outside=os.path.basename(outside_directory))
+
+  before = snapshot(outside_directory)
+
+  with pytest.raises(FileboxError) as error:
+    client.request(request_name, **values)
+
+  assert error.value.code == ERROR_PERMISSION
+  assert snapshot(outside_directory) == before
+  assert os.path.exists(os.path.join(server_directory, 'inside.txt'))
+
+
+@pytest.mark.parametrize('request_name,field', PATH_FIELDS)
+def test_absolute_paths(server_directory, outside_directory, client, request_name, field):
+  """
+  Absolute paths are relative to the shared directory, so an absolute path on
+  the server's disk can't reach the file it names there.
+  """
+
+  values = {'path': '/inside.txt', 'old_path': '/inside.txt', 'new_path': '/inside-new.txt'}
+  values = {name: values[name] for name in ('path', 'old_path', 'new_path') if name in MESSAGES[request_name][1]}
+  values.update(PATH_REQUEST_DEFAULTS.get(request_name, {}))
+  values[field] = os.path.join(outside_directory, 'victim')
+
+  before = snapshot(outside_directory)
+
+  # Requests that succeed, such as deleting a directory that doesn't exist,
+  # act on the shared directory.
+  try:
+    client.request(request_name, **values)
+  except FileboxError as error:
+    assert error.code in (ERROR_NOT_FOUND, ERROR_PERMISSION)
+
+  assert snapshot(outside_directory) == before
+
+
+def test_symlink_outside_root(server_directory, client):
+  for target in ['../outside', '/etc/passwd']:
+    with pytest.raises(FileboxError) as error:
+      client.request('CreateSymlinkRequest', path='/escape', target=target)
+
+    assert error.value.code == ERROR_PERMISSION
+
+  assert not os.path.lexists(os.path.join(server_directory, 'escape'))
+
+  # The target is followed from the directory that the link is created in,
+  # not from the path in the request, which may go through other links.
+  client.request('CreateSymlinkRequest', path='/loop', target='.')
+  try:
+    for path, target in [('/loop/loop/loop/x', '../../../etc'), ('/loop/x', 'loop/../..')]:
+      with pytest.raises(FileboxError) as error:
+        client.request('CreateSymlinkRequest', path=path, target=target)
+
+      assert error.value.code == ERROR_PERMISSION
+      assert not os.path.lexists(os.path.join(server_directory, 'x'))
+
+    client.request('CreateSymlinkRequest', path='/loop/loop/x', target='loop/loop')
+    assert os.readlink(os.path.join(server_directory, 'x')) == 'loop/loop'
+  finally:
+    for name in ['loop', 'x']:
+      if os.path.lexists(os.path.join(server_directory, name)):
+        os.remove(os.path.join(server_directory, name))
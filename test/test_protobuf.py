@@ -0,0 +1,169 @@
+import os
+import shutil
+import pytest
+from filebox import server_directory, client, ERROR_NOT_FOUND, ERROR_PERMISSION, ERROR_NO_ATTRIBUTE
+from filebox_protobuf import FileboxError, NO_FILE_HANDLE
+
+# MaxDirectoryPage from pkg/protocol/codec.go
+MAX_DIRECTORY_PAGE = 1024
+
+
+def test_file_operations(server_directory, client):
+  """
+  Run the file requests through the protobuf encoding, from a client that isn't
+  written in Go, and check their effect on the shared directory. TestEncodings
+  in pkg/client sends the same requests over gob and protobuf, and checks that
+  their results are the same.
+  """
+
+  client.request('CreateDirectoryRequest', path='/pb', mode=0o755)
+  assert os.path.isdir(os.path.join(server_directory, 'pb'))
+
+  _, response = client.request('OpenFileRequest', path='/pb/file.txt', flags=os.O_CREAT | os.O_RDWR)
+  file_handle = response['file_handle']
+
+  _, response = client.request('WriteFileRequest', file_handle=file_handle, offset=0, data=b'hello protobuf')
+  assert response['bytes_written'] == len(b'hello protobuf')
+
+  client.request('FsyncFileRequest', file_handle=file_handle)
+  client.request('FsyncDirectoryRequest', path='/pb')
+
+  _, response = client.request('ReadFileRequest', file_handle=file_handle, offset=6, size=100)
+  assert response['data'][:response['bytes_read']] == b'protobuf'
+
+  client.request('TruncateRequest', file_handle=file_handle, size=5)
+
+  _, response = client.request('GetFileAttributesRequest', file_handle=file_handle)
+  assert response['file_info']['size'] == 5
+  assert not response['file_info']['is_dir']
+
+  client.request('CloseFileRequest', file_handle=file_handle)
+
+  client.request('ChangeModeRequest', path='/pb/file.txt', mode=0o640)
+  client.request('ChangeTimesRequest', path='/pb/file.txt', access_time=1000 * 10**9, mod_time=2000 * 10**9)
+
+  stat = os.stat(os.path.join(server_directory, 'pb', 'file.txt'))
+  assert stat.st_mode & 0o777 == 0o640
+  assert (stat.st_atime, stat.st_mtime) == (1000, 2000)
+
+  _, response = client.request('GetFileAttributesRequest', path='/pb/file.txt', file_handle=NO_FILE_HANDLE)
+  assert response['file_info']['uid'] == stat.st_uid
+  assert response['file_info']['access_time'] == 1000 * 10**9
+
+  with open(os.path.join(server_directory, 'pb', 'file.txt'), 'rb') as f:
+    assert f.read() == b'hello'
+
+  client.request('CreateSymlinkRequest', path='/pb/link', target='file.txt')
+  assert os.readlink(os.path.join(server_directory, 'pb', 'link')) == 'file.txt'
+
+  _, response = client.request('ReadSymlinkRequest', path='/pb/link')
+  assert response['target'] == 'file.txt'
+
+  client.request('CreateHardLinkRequest', old_path='/pb/file.txt', new_path='/pb/hard')
+  assert os.stat(os.path.join(server_directory, 'pb', 'file.txt')).st_nlink == 2
+
+  client.request('DeleteFileRequest', path='/pb/link')
+  client.request('DeleteFileRequest', path='/pb/hard')
+
+  client.request('RenameRequest', old_path='/pb/file.txt', new_path='/pb/renamed.txt')
+
+  _, response = client.request('ReadDirectoryRequest', path='/pb')
+  assert [file['name'] for file in response['files']] == ['renamed.txt']
+
+  client.request('DeleteFileRequest', path='/pb/renamed.txt')
+  client.request('DeleteDirectoryRequest', path='/pb')
+  assert not os.path.exists(os.path.join(server_directory, 'pb'))
+
+
+def test_errors(client):
+  with pytest.raises(FileboxError) as error:
+    client.request('GetFileAttributesRequest', path='/missing', file_handle=NO_FILE_HANDLE)
+
+  assert error.value.code == ERROR_NOT_FOUND
+
+
+def test_read_directory_pages(server_directory, client):
+  directory = os.path.join(server_directory, 'pages')
+  os.mkdir(directory)
+  names = sorted('file-{:04}'.format(i) for i in range(2500))
+  for name in names:
+    open(os.path.join(directory, name), 'w').close()
+
+  try:
+    # A limit of 0 lists the whole directory, like before pages existed.
+    _, response = client.request('ReadDirectoryRequest', path='/pages')
+    assert [file['name'] for file in response['files']] == names
+    assert not response['more']
+
+    listed, after = [], ''
+    while True:
+      _, response = client.request('ReadDirectoryRequest', path='/pages', after=after, limit=1000)
+      assert len(response['files']) <= 1000
+      listed += [file['name'] for file in response['files']]
+      if not response['more']:
+        break
+      after = listed[-1]
+    assert listed == names
+
+    # Larger limits are capped, and a page continues after a deleted name.
+    _, response = client.request('ReadDirectoryRequest', path='/pages', limit=10 * MAX_DIRECTORY_PAGE)
+    assert len(response['files']) == MAX_DIRECTORY_PAGE
+    assert response['more']
+
+    os.remove(os.path.join(directory, names[9]))
+    _, response = client.request('ReadDirectoryRequest', path='/pages', after=names[9], limit=1)
+    assert [file['name'] for file in response['files']] == [names[10]]
+  finally:
+    shutil.rmtree(directory)
+
+
+def test_extended_attributes(server_directory, client):
+  path = os.path.join(server_directory, 'xattr.txt')
+  open(path, 'w').close()
+
+  try:
+    os.setxattr(path, 'user.probe', b'')
+  except (AttributeError, OSError):
+    pytest.skip('the server directory does not support extended attributes')
+
+  client.request('SetExtendedAttributeRequest', path='/xattr.txt', name='user.color', value=b'blue', flags=1)
+  assert os.getxattr(path, 'user.color') == b'blue'
+
+  _, response = client.request('GetExtendedAttributeRequest', path='/xattr.txt', name='user.color')
+  assert response['value'] == b'blue'
+
+  _, response = client.request('ListExtendedAttributesRequest', path='/xattr.txt')
+  assert sorted(response['names']) == ['user.color', 'user.probe']
+
+  client.request('RemoveExtendedAttributeRequest', path='/xattr.txt', name='user.color')
+
+  with pytest.raises(FileboxError) as error:
+    client.request('GetExtendedAttributeRequest', path='/xattr.txt', name='user.color')
+
+  assert error.value.code == ERROR_NO_ATTRIBUTE
+
+  # Only the user namespace can be changed.
+  for request, values in [('SetExtendedAttributeRequest', {'value': b'x', 'flags': 0}), ('RemoveExtendedAttributeRequest', {})]:
+    with pytest.raises(FileboxError) as error:
+      client.request(request, path='/xattr.txt', name='trusted.color', **values)
+
+    assert error.value.code == ERROR_PERMISSION
+
+  os.remove(path)
+
+
+def test_change_mode_and_owner(server_directory, client):
+  path = os.path.join(server_directory, 'owned.txt')
+  open(path, 'w').close()
+
+  # The setuid and setgid bits are dropped, since the server doesn't allow them.
+  client.request('ChangeModeRequest', path='/owned.txt', mode=0o6755)
+  assert os.stat(path).st_mode & 0o7777 == 0o755
+
+  with pytest.raises(FileboxError) as error:
+    client.request('ChangeOwnerRequest', path='/owned.txt', uid=os.getuid() + 1, gid=-1)
+
+  assert error.value.code == ERROR_PERMISSION
+
+  client.request('ChangeOwnerRequest', path='/owned.txt', uid=os.getuid(), gid=os.getgid())
+  os.remove(path)
@@ -0,0 +1,133 @@
+import os
+import json
+import tempfile
+import pytest
+from filebox import filebox_server, connect, FILEBOX_TEST_PORT, ERROR_QUOTA_EXCEEDED
+from filebox_protobuf import FileboxError
+
+
+@pytest.fixture(scope="module")
+def quota_server():
+  """
+  A server with a quota for alice and for /limited, where bob is an admin and
+  carol has no quota.
+  """
+
+  with tempfile.TemporaryDirectory() as config_directory:
+    tokens_file = os.path.join(config_directory, 'tokens.txt')
+    with open(tokens_file, 'w') as f:
+      f.write('alice:alice-token\nbob:bob-token\ncarol:carol-token\n')
+
+    quotas_file = os.path.join(config_directory, 'quotas.json')
+    with open(quotas_file, 'w') as f:
+      json.dump({
+        'quotas': [{'user': 'alice', 'limit': 100}, {'path': '/limited', 'limit': 50}],
+        'admins': ['bob'],
+      }, f)
+
+    port = FILEBOX_TEST_PORT + 1
+    with filebox_server(port, ['--tokens-file', tokens_file, '--quotas', quotas_file]) as directory:
+      yield directory, port
+
+
+def quota_usage(client):
+  _, response = client.request('QuotaUsageRequest')
+  return {quota['user'] or quota['path']: quota['usage'] for quota in response['quotas']}
+
+
+def test_quotas(quota_server):
+  server_directory, port = quota_server
+  alice = connect(port, 'alice', 'alice-token')
+
+  try:
+    _, response = alice.request('OpenFileRequest', path='/alice.txt', flags=os.O_CREAT | os.O_RDWR)
+    file_handle = response['file_handle']
+    alice.request('WriteFileRequest', file_handle=file_handle, offset=0, data=b'a' * 80)
+    assert quota_usage(alice)['alice'] == 80
+
+    with pytest.raises(FileboxError) as error:
+      alice.request('WriteFileRequest', file_handle=file_handle, offset=80, data=b'a' * 30)
+
+    assert error.value.code == ERROR_QUOTA_EXCEEDED
+    assert os.path.getsize(os.path.join(server_directory, 'alice.txt')) == 80
+
+    # Moving the file into a directory with a smaller quota, under a new name
+    # or under a second one, fails too.
+    alice.request('CreateDirectoryRequest', path='/limited', mode=0o755)
+
+    for request in ['RenameRequest', 'CreateHardLinkRequest']:
+      with pytest.raises(FileboxError) as error:
+        alice.request(request, old_path='/alice.txt', new_path='/limited/alice.txt')
+
+      assert error.value.code == ERROR_QUOTA_EXCEEDED
+      assert not os.path.exists(os.path.join(server_directory, 'limited', 'alice.txt'))
+
+    alice.request('CloseFileRequest', file_handle=file_handle)
+    alice.request('DeleteFileRequest', path='/alice.txt')
+    assert quota_usage(alice)['alice'] == 0
+  finally:
+    alice.close()
+
+
+def test_quota_after_delete_directory(quota_server):
+  """
+  Deleting a directory releases the storage of every file under it.
+  """
+  _, port = quota_server
+  alice = connect(port, 'alice', 'alice-token')
+
+  try:
+    alice.request('CreateDirectoryRequest', path='/alice', mode=0o755)
+    _, response = alice.request('OpenFileRequest', path='/alice/a.txt', flags=os.O_CREAT | os.O_RDWR)
+    alice.request('WriteFileRequest', file_handle=response['file_handle'], offset=0, data=b'a' * 90)
+    alice.request('CloseFileRequest', file_handle=response['file_handle'])
+    assert quota_usage(alice)['alice'] == 90
+
+    alice.request('DeleteDirectoryRequest', path='/alice')
+    assert quota_usage(alice)['alice'] == 0
+
+    _, response = alice.request('OpenFileRequest', path='/b.txt', flags=os.O_CREAT | os.O_RDWR)
+    alice.request('WriteFileRequest', file_handle=response['file_handle'], offset=0, data=b'b' * 50)
+    alice.request('CloseFileRequest', file_handle=response['file_handle'])
+    alice.request('DeleteFileRequest', path='/b.txt')
+  finally:
+    alice.close()
+
+
+def test_quota_after_rename_of_open_file(quota_server):
+  """
+  Writes through a file handle are counted after the file is renamed.
+  """
+  server_directory, port = quota_server
+  alice = connect(port, 'alice', 'alice-token')
+
+  try:
+    _, response = alice.request('OpenFileRequest', path='/f.txt', flags=os.O_CREAT | os.O_RDWR)
+    file_handle = response['file_handle']
+    alice.request('RenameRequest', old_path='/f.txt', new_path='/g.txt')
+
+    with pytest.raises(FileboxError) as error:
+      alice.request('WriteFileRequest', file_handle=file_handle, offset=0, data=b'a' * 1000)
+
+    assert error.value.code == ERROR_QUOTA_EXCEEDED
+    assert os.path.getsize(os.path.join(server_directory, 'g.txt')) == 0
+
+    alice.request('WriteFileRequest', file_handle=file_handle, offset=0, data=b'a' * 60)
+    assert quota_usage(alice)['alice'] == 60
+
+    alice.request('CloseFileRequest', file_handle=file_handle)
+    alice.request('DeleteFileRequest', path='/g.txt')
+    assert quota_usage(alice)['alice'] == 0
+  finally:
+    alice.close()
+
+
+def test_quota_usage_visibility(quota_server):
+  _, port = quota_server
+
+  for username, expected in [('alice', ['alice', '/limited']), ('carol', ['/limited']), ('bob', ['alice', '/limited'])]:
+    client = connect(port, username, username + '-token')
+    try:
+      assert sorted(quota_usage(client)) == sorted(expected)
+    finally:
+      client.close()
@@ -0,0 +1,33 @@
+import os
+from filebox import filebox_server, server_directory, connect, client, FILEBOX_TEST_PORT
+
+
+def test_statfs(server_directory, client):
+  _, response = client.request('StatfsRequest', path='/')
+  expected = os.statvfs(server_directory)
+
+  assert response['block_size'] == expected.f_frsize
+  assert response['blocks'] == expected.f_blocks
+  assert response['files'] == expected.f_files
+  assert response['name_length'] == expected.f_namemax
+
+
+def test_statfs_quota():
+  """With --quota, the free space is the quota minus what the files use."""
+
+  port = FILEBOX_TEST_PORT + 3
+  with filebox_server(port, ['--quota', '1MB']):
+    client = connect(port)
+
+    try:
+      _, response = client.request('OpenFileRequest', path='/data', flags=os.O_CREAT | os.O_RDWR)
+      client.request('WriteFileRequest', file_handle=response['file_handle'], offset=0, data=b'x' * 256 * 1024)
+      client.request('CloseFileRequest', file_handle=response['file_handle'])
+
+      _, response = client.request('StatfsRequest', path='/')
+      block_size = response['block_size']
+      assert response['blocks'] == (1 << 20) // block_size
+      assert response['blocks_free'] == (768 * 1024) // block_size
+      assert response['blocks_available'] == (768 * 1024) // block_size
+    finally:
+      client.close()
@@ -0,0 +1,74 @@
+import os
+from concurrent.futures import ThreadPoolExecutor
+from filebox import shared_directories
+
+THREADS = 64
+READS = 2000
+CHUNK_SIZE = 4096
+
+
+def test_parallel_reads(shared_directories):
+  """
+   - Write a file directly to the server's directory.
+   - Read random chunks of it through a single client, from many threads at once.
+   - Make sure every read returns the correct data.
+
+  All the reads go through the same connection, so this test makes sure the
+  server keeps responses intact when many requests are handled in parallel.
+  """
+
+  # NOTE: The first shared directory is the server's directory, and the rest
+  #       are managed by Filebox clients. This logic is defined in filebox.py.
+  server_directory, client_directory = shared_directories[0], shared_directories[1]
+
+  content = os.urandom(CHUNK_SIZE * 256)
+  with open(os.path.join(server_directory, 'stress.bin'), 'wb') as f:
+    f.write(content)
+
+  def read_chunk(i):
+    offset = (i * 7919 % 256) * CHUNK_SIZE
+    with open(os.path.join(client_directory, 'stress.bin'), 'rb') as f:
+      f.seek(offset)
+      return offset, f.read(CHUNK_SIZE)
+
+  with ThreadPoolExecutor(max_workers=THREADS) as executor:
+    for offset, data in executor.map(read_chunk, range(READS)):
+      assert data == content[offset:offset + CHUNK_SIZE]
+
+  os.remove(os.path.join(server_directory, 'stress.bin'))
+
+
+def test_parallel_writes(shared_directories):
+  """
+   - Write different chunks of a single file through a single client, from many
+     threads at once, while other threads read it.
+   - Make sure the server's copy of the file contains every chunk.
+  """
+
+  server_directory, client_directory = shared_directories[0], shared_directories[1]
+  client_path = os.path.join(client_directory, 'stress.bin')
+  chunks = [os.urandom(CHUNK_SIZE) for _ in range(256)]
+
+  with open(client_path, 'wb') as f:
+    f.truncate(CHUNK_SIZE * len(chunks))
+
+  def write_chunk(i):
+    with open(client_path, 'r+b') as f:
+      f.seek(i * CHUNK_SIZE)
+      f.write(chunks[i])
+
+  def read_chunk(i):
+    with open(client_path, 'rb') as f:
+      f.seek(i * CHUNK_SIZE)
+      return len(f.read(CHUNK_SIZE))
+
+  with ThreadPoolExecutor(max_workers=THREADS) as executor:
+    writes = executor.map(write_chunk, range(len(chunks)))
+    reads = executor.map(read_chunk, range(len(chunks)))
+    list(writes)
+    assert all(size == CHUNK_SIZE for size in reads)
+
+  with open(os.path.join(server_directory, 'stress.bin'), 'rb') as f:
+    assert f.read() == b''.join(chunks)
+
+  os.remove(client_path)
@@ -0,0 +1,79 @@
+/*
+ * host_lock_linux.go
+ *
+ * The lock and flock operations of struct fuse_operations, which filebox
+ * adds to cgofuse v1.1.0.
+ */
+/*
+ * This file is part of Cgofuse.
+ *
+ * It is licensed under the MIT license. The full license text can be found
+ * in the License.txt file at the root of this project.
+ */
+
+package fuse
+
+/*
+#include <stdint.h>
+#include <fcntl.h>
+#include <fuse.h>
+
+static inline void hostCflockFromFuselock(struct flock *lock,
+	int16_t type, int16_t whence, int64_t start, int64_t len, int32_t pid)
+{
+	lock->l_type = type;
+	lock->l_whence = whence;
+	lock->l_start = start;
+	lock->l_len = len;
+	lock->l_pid = pid;
+}
+*/
+import "C"
+
+func copyFuselockFromCflock(dst *Lock_t, src *C.struct_flock) {
+	*dst = Lock_t{
+		Type:   int16(src.l_type),
+		Whence: int16(src.l_whence),
+		Start:  int64(src.l_start),
+		Len:    int64(src.l_len),
+		Pid:    int(src.l_pid),
+	}
+}
+
+func copyCflockFromFuselock(dst *C.struct_flock, src *Lock_t) {
+	C.hostCflockFromFuselock(dst,
+		C.int16_t(src.Type),
+		C.int16_t(src.Whence),
+		C.int64_t(src.Start),
+		C.int64_t(src.Len),
+		C.int32_t(src.Pid))
+}
+
+//export hostLock
+func hostLock(path0 *C.char, fi0 *C.struct_fuse_file_info, cmd0 C.int, lock0 *C.struct_flock) (errc0 C.int) {
+	defer recoverAsErrno(&errc0)
+	fsop := hostHandleGet(C.fuse_get_context().private_data).fsop
+	intf, ok := fsop.(FileSystemLock)
+	if !ok {
+		return -C.int(ENOSYS)
+	}
+	path := C.GoString(path0)
+	lock := Lock_t{}
+	copyFuselockFromCflock(&lock, lock0)
+	errc := intf.Lock(path, int(cmd0), &lock, uint64(fi0.fh), uint64(fi0.lock_owner))
+	copyCflockFromFuselock(lock0, &lock)
+	return C.int(errc)
+}
+
+//export hostFlock
+func hostFlock(path0 *C.char, fi0 *C.struct_fuse_file_info, op0 C.int) (errc0 C.int) {
+	defer recoverAsErrno(&errc0)
+	fsop := hostHandleGet(C.fuse_get_context().private_data).fsop
+	intf, ok := fsop.(FileSystemFlock)
+	if !ok {
+		return -C.int(ENOSYS)
+	}
+	path := C.GoString(path0)
+	errc := intf.Flock(path, int(op0), uint64(fi0.fh), uint64(fi0.lock_owner))
+	return C.int(errc)
+}
@@ -0,0 +1,3 @@
+module github.com/billziss-gh/cgofuse
+
+go 1.13